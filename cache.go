@@ -0,0 +1,205 @@
+package avroschema
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"unsafe"
+)
+
+/*
+A finished schema of a root type, along with the configuration it was reflected with.
+*/
+type cachedSchema struct {
+	config reflectorConfig
+	schema *AvroSchema
+	json   string
+}
+
+/*
+The options of a Reflector which affect the schema it reflects.
+Functions are told apart by their closure pointer, so that closures of the same literal capturing different variables differ.
+Doc registries are told apart by identity, as they may be large and are not to be changed in place.
+*/
+type reflectorConfig struct {
+	BeBackwardTransitive bool
+	EmitAllFields        bool
+	SkipTagFieldNames    bool
+	MapKeys              MapKeyMode
+	NullablePointers     bool
+	Namespace            string
+	Strict               bool
+	DecimalPrecision     int
+	DecimalScale         int
+	BigDecimal           bool
+	TimePrecision        TimePrecision
+	LocalTimestamps      bool
+	DurationAsFixed      bool
+	UUIDAsFixed          bool
+	Mapper               unsafe.Pointer
+	Namespacer           unsafe.Pointer
+	Namer                unsafe.Pointer
+	NameMapping          map[string]string
+	Docs                 unsafe.Pointer
+	Enums                map[reflect.Type]EnumSpec
+	Unions               map[reflect.Type]UnionSpec
+}
+
+func (r *Reflector) config() reflectorConfig {
+	return reflectorConfig{
+		BeBackwardTransitive: r.BeBackwardTransitive,
+		EmitAllFields:        r.EmitAllFields,
+		SkipTagFieldNames:    r.SkipTagFieldNames,
+		MapKeys:              r.MapKeys,
+		NullablePointers:     r.NullablePointers,
+		Namespace:            r.Namespace,
+		Strict:               r.Strict,
+		DecimalPrecision:     r.DecimalPrecision,
+		DecimalScale:         r.DecimalScale,
+		BigDecimal:           r.BigDecimal,
+		TimePrecision:        r.TimePrecision,
+		LocalTimestamps:      r.LocalTimestamps,
+		DurationAsFixed:      r.DurationAsFixed,
+		UUIDAsFixed:          r.UUIDAsFixed,
+		Mapper:               funcPointer(&r.Mapper),
+		Namespacer:           funcPointer(&r.Namespacer),
+		Namer:                funcPointer(&r.Namer),
+		NameMapping:          r.NameMapping,
+		Docs:                 reflect.ValueOf(r.Docs).UnsafePointer(),
+		Enums:                r.Enums,
+		Unions:               r.Unions,
+	}
+}
+
+/*
+A copy of c which doesn't share the registered maps and their specs with the Reflector,
+so that changing them in place is noticed.
+*/
+func (c reflectorConfig) clone() reflectorConfig {
+	c.NameMapping = maps.Clone(c.NameMapping)
+	if c.Enums != nil {
+		enums := make(map[reflect.Type]EnumSpec, len(c.Enums))
+		for t, spec := range c.Enums {
+			enums[t] = EnumSpec{Symbols: slices.Clone(spec.Symbols), Default: spec.Default}
+		}
+		c.Enums = enums
+	}
+	if c.Unions != nil {
+		unions := make(map[reflect.Type]UnionSpec, len(c.Unions))
+		for t, spec := range c.Unions {
+			unions[t] = UnionSpec{Members: slices.Clone(spec.Members), Nullable: spec.Nullable}
+		}
+		c.Unions = unions
+	}
+	return c
+}
+
+func funcPointer[F any](f *F) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(f))
+}
+
+/*
+Look up the finished schema of t, unless the configuration has changed since it was reflected.
+*/
+func (r *Reflector) cached(t reflect.Type) (*cachedSchema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.cache[t]
+	if !ok || !reflect.DeepEqual(entry.config, r.config()) {
+		return nil, false
+	}
+	return entry, true
+}
+
+/*
+Reflect t under the write lock, so that concurrent calls reflect it once.
+Only the latest configuration is kept per type, so the cache is bounded by the number of root types.
+*/
+func (r *Reflector) reflectCached(t reflect.Type) (*cachedSchema, error) {
+	if entry, ok := r.cached(t); ok {
+		return entry, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	config := r.config()
+	if entry, ok := r.cache[t]; ok && reflect.DeepEqual(entry.config, config) {
+		return entry, nil
+	}
+
+	schema, err := r.reflectRoot(t)
+	if err != nil {
+		return nil, err
+	}
+	json, err := StructToJson(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &cachedSchema{config: config.clone(), schema: schema, json: json}
+	if r.cache == nil {
+		r.cache = make(map[reflect.Type]*cachedSchema)
+	}
+	r.cache[t] = entry
+	return entry, nil
+}
+
+/*
+Deep copy of a schema, so that callers may modify what ReflectSchema returns without corrupting the cache.
+*/
+func cloneSchema(v any) any {
+	switch s := v.(type) {
+	case *AvroSchema:
+		if s == nil {
+			return s
+		}
+		c := *s
+		c.Type = cloneSchema(s.Type)
+		c.Items = cloneSchema(s.Items)
+		c.Values = cloneSchema(s.Values)
+		c.Fields = cloneSchema(s.Fields).([]*AvroSchema)
+		c.Aliases = slices.Clone(s.Aliases)
+		c.Symbols = slices.Clone(s.Symbols)
+		c.Default = cloneDefault(s.Default)
+		return &c
+	case []*AvroSchema:
+		if s == nil {
+			return s
+		}
+		c := make([]*AvroSchema, len(s))
+		for i, f := range s {
+			c[i] = cloneSchema(f).(*AvroSchema)
+		}
+		return c
+	case []any:
+		c := make([]any, len(s))
+		for i, b := range s {
+			c[i] = cloneSchema(b)
+		}
+		return c
+	}
+	return v
+}
+
+/*
+Deep copy of a default value, i.e. of the objects and arrays decoded from JSON.
+*/
+func cloneDefault(v any) any {
+	switch d := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(d))
+		for k, e := range d {
+			c[k] = cloneDefault(e)
+		}
+		return c
+	case []any:
+		c := make([]any, len(d))
+		for i, e := range d {
+			c[i] = cloneDefault(e)
+		}
+		return c
+	}
+	return v
+}
@@ -0,0 +1,184 @@
+package avroschema
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+CanonicalForm returns the Parsing Canonical Form of s as specified by Avro: primitives in
+their simple form, full names instead of short ones, no attributes irrelevant to parsing such
+as doc, aliases, defaults and logical types, attributes in a fixed order and no whitespace.
+
+Two schemas with the same canonical form read and write the same data, which makes it the
+basis of schema fingerprints. s may be reflected or parsed, a reference to an undefined name
+is reported as a *ParseError.
+*/
+func CanonicalForm(s *AvroSchema) (string, error) {
+	c := &canonicalizer{names: indexSchema(s), defined: map[string]bool{}}
+	if err := c.write(s, "$", ""); err != nil {
+		return "", err
+	}
+	return c.String(), nil
+}
+
+/*
+Fingerprint64 is the CRC-64-AVRO (Rabin) fingerprint of the canonical form of s,
+as used by single-object encoding.
+*/
+func Fingerprint64(s *AvroSchema) (uint64, error) {
+	canonical, err := CanonicalForm(s)
+	if err != nil {
+		return 0, err
+	}
+	return rabin([]byte(canonical)), nil
+}
+
+/*
+FingerprintMD5 is the MD5 fingerprint of the canonical form of s.
+*/
+func FingerprintMD5(s *AvroSchema) ([md5.Size]byte, error) {
+	canonical, err := CanonicalForm(s)
+	if err != nil {
+		return [md5.Size]byte{}, err
+	}
+	return md5.Sum([]byte(canonical)), nil
+}
+
+/*
+FingerprintSHA256 is the SHA-256 fingerprint of the canonical form of s.
+*/
+func FingerprintSHA256(s *AvroSchema) ([sha256.Size]byte, error) {
+	canonical, err := CanonicalForm(s)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256([]byte(canonical)), nil
+}
+
+const rabinEmpty = 0xc15d213aa4d7a795
+
+var rabinTable = func() (table [256]uint64) {
+	for i := range table {
+		fp := uint64(i)
+		for j := 0; j < 8; j++ {
+			fp = (fp >> 1) ^ (rabinEmpty & -(fp & 1))
+		}
+		table[i] = fp
+	}
+	return table
+}()
+
+func rabin(data []byte) uint64 {
+	fp := uint64(rabinEmpty)
+	for _, b := range data {
+		fp = (fp >> 8) ^ rabinTable[byte(fp)^b]
+	}
+	return fp
+}
+
+type canonicalizer struct {
+	strings.Builder
+	names   namedSchemas
+	defined map[string]bool // named types written out so far, later occurrences are written by name
+}
+
+/*
+Write the canonical form of s, the path is a JSON path to s for error reporting.
+*/
+func (c *canonicalizer) write(s any, path, namespace string) error {
+	switch v := s.(type) {
+	case string:
+		if primitiveTypes[v] {
+			c.WriteString(strconv.Quote(v))
+			return nil
+		}
+		named, ok := c.names.lookup(v, namespace)
+		if !ok {
+			return &ParseError{Path: path, Reason: fmt.Sprintf("undefined name %q", v)}
+		}
+		return c.write(named.schema, path, named.namespace)
+	case []any:
+		c.WriteByte('[')
+		for i, b := range v {
+			if i > 0 {
+				c.WriteByte(',')
+			}
+			if err := c.write(b, fmt.Sprintf("%s[%d]", path, i), namespace); err != nil {
+				return err
+			}
+		}
+		c.WriteByte(']')
+		return nil
+	case *AvroSchema:
+		return c.writeSchema(v, path, namespace)
+	}
+	return &ParseError{Path: path, Reason: fmt.Sprintf("unexpected %T", s)}
+}
+
+func (c *canonicalizer) writeSchema(s *AvroSchema, path, namespace string) error {
+	typ, ok := s.Type.(string)
+	if !ok {
+		// a field or a top-level union
+		return c.write(s.Type, path+".type", namespace)
+	}
+
+	switch typ {
+	case "record", "error", "enum", "fixed":
+		full, ns := fullName(s.Name, s.Namespace, namespace)
+		if c.defined[full] {
+			c.WriteString(strconv.Quote(full))
+			return nil
+		}
+		c.defined[full] = true
+		fmt.Fprintf(c, `{"name":%s,"type":%s`, strconv.Quote(full), strconv.Quote(typ))
+		switch typ {
+		case "enum":
+			c.WriteString(`,"symbols":[`)
+			for i, sym := range s.Symbols {
+				if i > 0 {
+					c.WriteByte(',')
+				}
+				c.WriteString(strconv.Quote(sym))
+			}
+			c.WriteByte(']')
+		case "fixed":
+			fmt.Fprintf(c, `,"size":%d`, s.Size)
+		default:
+			c.WriteString(`,"fields":[`)
+			for i, f := range s.Fields {
+				if i > 0 {
+					c.WriteByte(',')
+				}
+				fpath := fmt.Sprintf("%s.fields[%d]", path, i)
+				fmt.Fprintf(c, `{"name":%s,"type":`, strconv.Quote(f.Name))
+				if err := c.write(fieldType(f), fpath+".type", ns); err != nil {
+					return err
+				}
+				c.WriteByte('}')
+			}
+			c.WriteByte(']')
+		}
+		c.WriteByte('}')
+		return nil
+	case "array":
+		c.WriteString(`{"type":"array","items":`)
+		if err := c.write(s.Items, path+".items", namespace); err != nil {
+			return err
+		}
+		c.WriteByte('}')
+		return nil
+	case "map":
+		c.WriteString(`{"type":"map","values":`)
+		if err := c.write(s.Values, path+".values", namespace); err != nil {
+			return err
+		}
+		c.WriteByte('}')
+		return nil
+	}
+	// primitives, possibly with a logical type, and wrapped references
+	return c.write(typ, path+".type", namespace)
+}
@@ -0,0 +1,104 @@
+package avroschema
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint64(t *testing.T) {
+	// CRC-64-AVRO fingerprints of the Avro test suite
+	tests := []struct {
+		schema      string
+		fingerprint int64
+	}{
+		{"null", 7195948357588979594},
+		{"boolean", -6970731678124411036},
+		{"int", 8247732601305521295},
+		{"long", -3434872931120570953},
+		{"float", 5583340709985441680},
+		{"double", -8181574048448539266},
+		{"bytes", 5746618253357095269},
+		{"string", -8142146995180207161},
+	}
+
+	for _, test := range tests {
+		fp, err := Fingerprint64(&AvroSchema{Type: test.schema})
+		assert.Nil(t, err)
+		assert.Equal(t, test.fingerprint, int64(fp), test.schema)
+	}
+}
+
+func TestCanonicalForm(t *testing.T) {
+	avsc := `{
+    "name": "Order",
+    "type": "record",
+    "namespace": "com.example",
+    "doc": "A customer order",
+    "aliases": ["Purchase"],
+    "fields": [
+      {"name": "id", "type": {"type": "string", "logicalType": "uuid"}},
+      {"name": "status", "type": {"name": "Status", "type": "enum", "namespace": "com.example.types", "symbols": ["OPEN", "PAID"], "default": "OPEN"}},
+      {"name": "previous", "type": "com.example.types.Status", "doc": "before the last change"},
+      {"name": "hash", "type": {"name": "md5", "type": "fixed", "size": 16}},
+      {"name": "placed", "type": "long", "logicalType": "timestamp-millis"},
+      {"name": "items", "type": {"type": "array", "items": {"name": "Item", "type": "record", "fields": [
+        {"name": "sku", "type": {"type": "string"}, "aliases": ["code"]},
+        {"name": "quantity", "type": "int", "default": 1, "order": "descending"}
+      ]}}},
+      {"name": "labels", "type": {"type": "map", "values": "string"}, "default": {}},
+      {"name": "parent", "type": ["null", "Order"], "default": null}
+    ]
+  }`
+
+	expected := `{"name":"com.example.Order","type":"record","fields":[` +
+		`{"name":"id","type":"string"},` +
+		`{"name":"status","type":{"name":"com.example.types.Status","type":"enum","symbols":["OPEN","PAID"]}},` +
+		`{"name":"previous","type":"com.example.types.Status"},` +
+		`{"name":"hash","type":{"name":"com.example.md5","type":"fixed","size":16}},` +
+		`{"name":"placed","type":"long"},` +
+		`{"name":"items","type":{"type":"array","items":{"name":"com.example.Item","type":"record","fields":[` +
+		`{"name":"sku","type":"string"},{"name":"quantity","type":"int"}]}}},` +
+		`{"name":"labels","type":{"type":"map","values":"string"}},` +
+		`{"name":"parent","type":["null","com.example.Order"]}]}`
+
+	s, err := ParseSchema([]byte(avsc))
+	assert.Nil(t, err)
+	r, err := CanonicalForm(s)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, r)
+
+	_, err = CanonicalForm(&AvroSchema{Name: "A", Type: "record", Fields: []*AvroSchema{{Name: "b", Type: "B"}}})
+	assert.NotNil(t, err)
+}
+
+func TestFingerprint(t *testing.T) {
+	type Order struct {
+		ID string `json:"id" avro:",doc=the order id"`
+	}
+
+	reflected, err := (&Reflector{Namespace: "com.example"}).ReflectSchema(&Order{})
+	assert.Nil(t, err)
+	parsed, err := ParseSchema([]byte(`{"type": "record", "name": "Order", "namespace": "com.example", "fields": [{"name": "id", "type": "string"}]}`))
+	assert.Nil(t, err)
+
+	// docs don't matter
+	for _, s := range []*AvroSchema{reflected, parsed} {
+		r, err := CanonicalForm(s)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"name":"com.example.Order","type":"record","fields":[{"name":"id","type":"string"}]}`, r)
+
+		md5, err := FingerprintMD5(s)
+		assert.Nil(t, err)
+		assert.Equal(t, "76c6466bf39c248ef92f2f18967057cf", hex.EncodeToString(md5[:]))
+
+		sha256, err := FingerprintSHA256(s)
+		assert.Nil(t, err)
+		assert.Equal(t, "00bfbf231f9aa4b18f0eed3a4ae0114c3300b56c44d8712e9d55cebed0301944", hex.EncodeToString(sha256[:]))
+	}
+
+	md5, err := FingerprintMD5(&AvroSchema{Type: "int"})
+	assert.Nil(t, err)
+	assert.Equal(t, "ef524ea1b91e73173d938ade36c1db32", hex.EncodeToString(md5[:]))
+}
@@ -0,0 +1,476 @@
+/*
+Command avroschema writes the Avro schemas of Go types to .avsc files, e.g. from a go:generate directive:
+
+	//go:generate go run github.com/wirelessr/avroschema/cmd/avroschema -type Order -namespace com.example
+
+It loads the Go package in the given directory, the current one by default, and reflects the types
+listed by -type, or else every type whose doc comment carries the marker
+
+	//avroschema:generate
+
+into a file per type named after it, e.g. order.avsc. The types are reflected by a temporary program
+importing the package, or with -static from source by package static, which main packages always are.
+
+The doc comments of the types of the package and their fields become the docs of the schemas. With
+-docs, they are also written as a DocRegistry to a Go file of the package, named relative to its
+directory, for the Reflector.Docs of the program itself:
+
+	//go:generate go run github.com/wirelessr/avroschema/cmd/avroschema -docs avro_docs.go
+
+Usage:
+
+	avroschema [flags] [dir]
+*/
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/wirelessr/avroschema"
+	"github.com/wirelessr/avroschema/static"
+)
+
+const marker = "//avroschema:generate"
+
+type options struct {
+	dir                  string
+	out                  string
+	types                []string
+	namespace            string
+	emitAllFields        bool
+	beBackwardTransitive bool
+	nameMapping          map[string]string
+	static               bool
+	docs                 string
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		// the errors of the avroschema package carry the prefix already
+		msg := err.Error()
+		if !strings.HasPrefix(msg, "avroschema: ") {
+			msg = "avroschema: " + msg
+		}
+		fmt.Fprintln(os.Stderr, msg)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	opts, err := parseFlags(args, stderr)
+	if err != nil {
+		return err
+	}
+
+	pkg, err := goList(opts.dir)
+	if err != nil {
+		return err
+	}
+	types, err := findTypes(opts.dir, pkg, opts.types)
+	if err != nil {
+		return err
+	}
+
+	docs, err := static.ParseDocs(opts.dir)
+	if err != nil {
+		return err
+	}
+	if opts.docs != "" {
+		if err := writeDocs(opts.docs, pkg.Name, docs); err != nil {
+			return err
+		}
+	}
+
+	if len(types) == 0 {
+		if opts.docs != "" {
+			return nil
+		}
+		return fmt.Errorf("no types to reflect, list them with -type or mark them with %s", marker)
+	}
+	// main packages cannot be imported
+	if opts.static || pkg.Name == "main" {
+		return reflectStatic(opts, types, docs)
+	}
+	return reflectTypes(opts, pkg.ImportPath, types, docs, stderr)
+}
+
+func parseFlags(args []string, stderr io.Writer) (*options, error) {
+	opts := &options{nameMapping: map[string]string{}}
+	fs := flag.NewFlagSet("avroschema", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: avroschema [flags] [dir]")
+		fs.PrintDefaults()
+	}
+
+	types := fs.String("type", "", "comma-separated `names` of the types to reflect, instead of the marked ones")
+	fs.StringVar(&opts.out, "out", "", "`dir`ectory to write the .avsc files to, the package directory by default")
+	fs.StringVar(&opts.namespace, "namespace", "", "`namespace` of the schemas")
+	fs.BoolVar(&opts.emitAllFields, "emit-all-fields", false, "include struct fields without tags")
+	fs.BoolVar(&opts.beBackwardTransitive, "backward-transitive", false, "make all fields optional")
+	fs.BoolVar(&opts.static, "static", false, "reflect the types from source, without importing the package")
+	fs.StringVar(&opts.docs, "docs", "", "write the doc comments of the package as a DocRegistry to the Go `file` AvroDocs, relative to the package directory")
+	fs.Func("name-mapping", "rename a record, as `Go=Avro`, may be repeated", func(s string) error {
+		from, to, ok := strings.Cut(s, "=")
+		if !ok || from == "" || to == "" {
+			return fmt.Errorf("expected Go=Avro, got %q", s)
+		}
+		opts.nameMapping[from] = to
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	switch fs.NArg() {
+	case 0:
+		opts.dir = "."
+	case 1:
+		opts.dir = fs.Arg(0)
+	default:
+		fs.Usage()
+		return nil, errors.New("at most one package directory expected")
+	}
+	if opts.out == "" {
+		opts.out = opts.dir
+	}
+	// the docs file belongs to the package, e.g. -docs avro_docs.go from a go:generate directive
+	if opts.docs != "" && !filepath.IsAbs(opts.docs) {
+		opts.docs = filepath.Join(opts.dir, opts.docs)
+	}
+	if *types != "" {
+		opts.types = strings.Split(*types, ",")
+	}
+	return opts, nil
+}
+
+/*
+Parse the Go files of the package in dir and find the named types, or the marked ones if names is empty.
+*/
+func findTypes(dir string, pkg *listedPackage, names []string) ([]string, error) {
+	fset := token.NewFileSet()
+	declared := map[string]*ast.TypeSpec{}
+	var marked []string
+	for _, name := range append(pkg.GoFiles, pkg.CgoFiles...) {
+		f, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				declared[ts.Name.Name] = ts
+				doc := ts.Doc
+				if doc == nil && len(gen.Specs) == 1 {
+					doc = gen.Doc
+				}
+				if hasMarker(doc) {
+					marked = append(marked, ts.Name.Name)
+				}
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		names = marked
+	}
+	for _, name := range names {
+		ts, ok := declared[name]
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in %s", name, dir)
+		}
+		if !ast.IsExported(name) {
+			return nil, fmt.Errorf("type %s is not exported", name)
+		}
+		if ts.TypeParams != nil {
+			return nil, fmt.Errorf("type %s is generic", name)
+		}
+		if !isStruct(ts.Type, declared) {
+			return nil, fmt.Errorf("type %s is not a struct type, only structs are reflected into records", name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+/*
+Whether the type expr denotes a struct type, following the types declared in the package.
+Types of other packages are taken to be structs, they are checked when reflected.
+*/
+func isStruct(expr ast.Expr, declared map[string]*ast.TypeSpec) bool {
+	seen := map[string]bool{}
+	for {
+		switch e := expr.(type) {
+		case *ast.StructType, *ast.SelectorExpr:
+			return true
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.IndexExpr:
+			// instances of generic types
+			expr = e.X
+		case *ast.IndexListExpr:
+			expr = e.X
+		case *ast.Ident:
+			ts, ok := declared[e.Name]
+			// predeclared types, or a cycle which doesn't type-check anyway
+			if !ok || seen[e.Name] {
+				return false
+			}
+			seen[e.Name] = true
+			expr = ts.Type
+		default:
+			return false
+		}
+	}
+}
+
+func hasMarker(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, c := range doc.List {
+		if strings.TrimSpace(c.Text) == marker {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+The output of `go list -json` the command needs.
+*/
+type listedPackage struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	GoFiles    []string // the files of the package for the current build context
+	CgoFiles   []string
+	Error      *struct{ Err string }
+}
+
+func goList(dir string) (*listedPackage, error) {
+	cmd := exec.Command("go", "list", "-e", "-json", ".")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	pkg := &listedPackage{}
+	if err := json.Unmarshal(out, pkg); err != nil {
+		return nil, fmt.Errorf("go list: %w", err)
+	}
+	if len(pkg.GoFiles)+len(pkg.CgoFiles) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	if pkg.Error != nil {
+		return nil, errors.New(pkg.Error.Err)
+	}
+	return pkg, nil
+}
+
+var program = template.Must(template.New("main").Funcs(template.FuncMap{"quote": strconv.Quote}).Parse(`// Code generated by avroschema. DO NOT EDIT.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/wirelessr/avroschema"
+	target {{quote .ImportPath}}
+)
+
+func main() {
+	reflector := &avroschema.Reflector{
+		Namespace:            {{quote .Namespace}},
+		EmitAllFields:        {{.EmitAllFields}},
+		BeBackwardTransitive: {{.BeBackwardTransitive}},
+		NameMapping: map[string]string{
+{{- range $from, $to := .NameMapping}}
+			{{quote $from}}: {{quote $to}},
+{{- end}}
+		},
+		Docs: avroschema.DocRegistry{
+{{- range $key, $doc := .Docs}}
+			{{quote $key}}: {{quote $doc}},
+{{- end}}
+		},
+	}
+
+	types := []struct {
+		file string
+		v    any
+	}{
+{{- range .Types}}
+		{ {{quote .File}}, &target.{{.Name}}{} },
+{{- end}}
+	}
+	for _, t := range types {
+		schema, err := reflector.ReflectSchema(t.v)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(filepath.Join({{quote .Out}}, t.file), append(data, '\n'), 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+`))
+
+type programType struct {
+	Name string
+	File string
+}
+
+/*
+Reflect the types by a temporary program importing the package. It lives within the package
+directory, so that the import resolves against the module of the package.
+*/
+func reflectTypes(opts *options, importPath string, types []string, docs avroschema.DocRegistry, stderr io.Writer) error {
+	out, err := filepath.Abs(opts.out)
+	if err != nil {
+		return err
+	}
+	data := struct {
+		ImportPath           string
+		Namespace            string
+		EmitAllFields        bool
+		BeBackwardTransitive bool
+		NameMapping          map[string]string
+		Docs                 avroschema.DocRegistry
+		Types                []programType
+		Out                  string
+	}{
+		ImportPath:           importPath,
+		Namespace:            opts.namespace,
+		EmitAllFields:        opts.emitAllFields,
+		BeBackwardTransitive: opts.beBackwardTransitive,
+		NameMapping:          opts.nameMapping,
+		Docs:                 docs,
+		Out:                  out,
+	}
+	for _, name := range types {
+		data.Types = append(data.Types, programType{Name: name, File: strings.ToLower(name) + ".avsc"})
+	}
+
+	dir, err := filepath.Abs(opts.dir)
+	if err != nil {
+		return err
+	}
+	var src bytes.Buffer
+	if err := program.Execute(&src, data); err != nil {
+		return err
+	}
+
+	// ignored by the go tool for being hidden
+	tmp, err := os.MkdirTemp(dir, ".avroschema")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	main := filepath.Join(tmp, "main.go")
+	if err := os.WriteFile(main, src.Bytes(), 0o644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", main)
+	cmd.Dir = dir
+	cmd.Stdout = stderr
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("reflecting %s: %w", strings.Join(types, ", "), err)
+	}
+	return nil
+}
+
+/*
+Reflect the types from source, for packages which cannot be imported.
+*/
+func reflectStatic(opts *options, types []string, docs avroschema.DocRegistry) error {
+	pkg, err := static.Load(opts.dir)
+	if err != nil {
+		return err
+	}
+	reflector := &avroschema.Reflector{
+		Namespace:            opts.namespace,
+		EmitAllFields:        opts.emitAllFields,
+		BeBackwardTransitive: opts.beBackwardTransitive,
+		NameMapping:          opts.nameMapping,
+		Docs:                 docs,
+	}
+	for _, name := range types {
+		schema, err := static.Reflect(reflector, pkg, name)
+		if err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(opts.out, strings.ToLower(name)+".avsc"), append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var docsFile = template.Must(template.New("docs").Funcs(template.FuncMap{"quote": strconv.Quote}).Parse(`// Code generated by avroschema. DO NOT EDIT.
+
+package {{.Package}}
+
+import "github.com/wirelessr/avroschema"
+
+// AvroDocs holds the doc comments of the types of the package and their fields, for Reflector.Docs.
+var AvroDocs = avroschema.DocRegistry{
+{{- range $key, $doc := .Docs}}
+	{{quote $key}}: {{quote $doc}},
+{{- end}}
+}
+`))
+
+func writeDocs(file, pkg string, docs avroschema.DocRegistry) error {
+	var src bytes.Buffer
+	data := struct {
+		Package string
+		Docs    avroschema.DocRegistry
+	}{pkg, docs}
+	if err := docsFile.Execute(&src, data); err != nil {
+		return err
+	}
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, formatted, 0o644)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wirelessr/avroschema"
+)
+
+func TestRun(t *testing.T) {
+	marked := map[string]string{
+		"customer.avsc": `{"name":"Customer","type":"record","fields":[{"name":"name","type":"string"}],"namespace":"com.example"}`,
+		"order.avsc":    `{"name":"Order","type":"record","doc":"Order is a placed order.","fields":[{"name":"id","type":"string","doc":"order number"},{"name":"items","type":{"type":"array","items":{"name":"Item","type":"record","fields":[{"name":"sku","type":"string"},{"name":"quantity","type":"int"}],"namespace":"com.example"}}},{"name":"coupon","type":["null","string"],"default":null},{"name":"placed_at","type":"long","logicalType":"timestamp-millis"}],"namespace":"com.example"}`,
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		expected map[string]string
+	}{
+		{
+			"marked types",
+			[]string{"-namespace", "com.example", "testdata/example"},
+			marked,
+		},
+		{
+			"marked types from source",
+			[]string{"-static", "-namespace", "com.example", "testdata/example"},
+			marked,
+		},
+		{
+			"listed types with options",
+			[]string{"-type", "Customer", "-emit-all-fields", "-backward-transitive", "-name-mapping", "Customer=Client", "testdata/example"},
+			map[string]string{
+				"customer.avsc": `{"name":"Client","type":"record","fields":[{"name":"name","type":["null","string"],"default":null},{"name":"Email","type":["null","string"],"default":null}]}`,
+			},
+		},
+		{
+			"from source",
+			[]string{"-static", "-type", "Customer", "-emit-all-fields", "testdata/example"},
+			map[string]string{
+				"customer.avsc": `{"name":"Customer","type":"record","fields":[{"name":"name","type":"string"},{"name":"Email","type":"string"}]}`,
+			},
+		},
+		{
+			"ignoring files excluded by build constraints",
+			[]string{"-type", "Ticket", "testdata/example"},
+			map[string]string{
+				"ticket.avsc": `{"name":"Ticket","type":"record","fields":[{"name":"status","type":{"name":"Status","type":"enum","symbols":["OPEN","CLOSED"],"doc":"Status symbols are only known at runtime."}}]}`,
+			},
+		},
+		{
+			"struct type declared from another",
+			[]string{"-type", "LineItem", "testdata/example"},
+			map[string]string{
+				"lineitem.avsc": `{"name":"LineItem","type":"record","doc":"LineItem is declared from another struct type.","fields":[{"name":"sku","type":"string"},{"name":"quantity","type":"int"}]}`,
+			},
+		},
+		{
+			"main package",
+			[]string{"testdata/command"},
+			map[string]string{
+				"config.avsc": `{"name":"Config","type":"record","doc":"Config of the server.","fields":[{"name":"addr","type":"string","doc":"Addr to listen on, e.g. \":8080\"."},{"name":"verbose","type":["null","boolean"],"default":null,"doc":"log requests"}]}`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		out := t.TempDir()
+		args := append([]string{"-out", out}, test.args...)
+		err := run(args, io.Discard)
+		assert.Nil(t, err, test.name)
+
+		entries, err := os.ReadDir(out)
+		assert.Nil(t, err, test.name)
+		assert.Len(t, entries, len(test.expected), test.name)
+		for file, expected := range test.expected {
+			data, err := os.ReadFile(filepath.Join(out, file))
+			assert.Nil(t, err, test.name)
+			schema, err := avroschema.ParseSchema(data)
+			assert.Nil(t, err, test.name)
+			actual, err := json.Marshal(schema)
+			assert.Nil(t, err, test.name)
+			assert.JSONEq(t, expected, string(actual), test.name)
+		}
+	}
+}
+
+func TestRunDocs(t *testing.T) {
+	out := t.TempDir()
+	file := filepath.Join(out, "avro_docs.go")
+	err := run([]string{"-docs", file, "-out", out, "testdata/command"}, io.Discard)
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(out, "config.avsc"))
+	assert.Nil(t, err)
+
+	data, err := os.ReadFile(file)
+	assert.Nil(t, err)
+	assert.Equal(t, `// Code generated by avroschema. DO NOT EDIT.
+
+package main
+
+import "github.com/wirelessr/avroschema"
+
+// AvroDocs holds the doc comments of the types of the package and their fields, for Reflector.Docs.
+var AvroDocs = avroschema.DocRegistry{
+	"main.Config":         "Config of the server.",
+	"main.Config.Addr":    "Addr to listen on, e.g. \":8080\".",
+	"main.Config.Verbose": "log requests",
+}
+`, string(data))
+}
+
+func TestParseFlagsDocs(t *testing.T) {
+	// relative to the package directory, absolute ones are kept
+	opts, err := parseFlags([]string{"-docs", "avro_docs.go", "testdata/command"}, io.Discard)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join("testdata", "command", "avro_docs.go"), opts.docs)
+
+	file := filepath.Join(t.TempDir(), "avro_docs.go")
+	opts, err = parseFlags([]string{"-docs", file, "testdata/command"}, io.Discard)
+	assert.Nil(t, err)
+	assert.Equal(t, file, opts.docs)
+}
+
+func TestRunError(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"unknown type", []string{"-type", "Invoice", "testdata/example"}, "type Invoice not found in testdata/example"},
+		{"bad name mapping", []string{"-name-mapping", "Customer", "testdata/example"}, `invalid value "Customer" for flag -name-mapping: expected Go=Avro, got "Customer"`},
+		{"no Go files", []string{"testdata"}, "no Go files in testdata"},
+		{"not a struct", []string{"-type", "Order,Status", "testdata/example"}, "type Status is not a struct type, only structs are reflected into records"},
+		{"not a struct from source", []string{"-static", "-type", "Order,Status", "testdata/example"}, "type Status is not a struct type, only structs are reflected into records"},
+	}
+
+	for _, test := range tests {
+		out := t.TempDir()
+		err := run(append([]string{"-out", out}, test.args...), io.Discard)
+		assert.EqualError(t, err, test.expected, test.name)
+
+		// nothing is written
+		entries, err := os.ReadDir(out)
+		assert.Nil(t, err, test.name)
+		assert.Empty(t, entries, test.name)
+	}
+}
@@ -0,0 +1,12 @@
+package main
+
+// Config of the server.
+//
+//avroschema:generate
+type Config struct {
+	// Addr to listen on, e.g. ":8080".
+	Addr    string `json:"addr"`
+	Verbose bool   `json:"verbose,omitempty"` // log requests
+}
+
+func main() {}
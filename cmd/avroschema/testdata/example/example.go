@@ -0,0 +1,42 @@
+package example
+
+import (
+	"strings"
+	"time"
+)
+
+// Order is a placed order.
+//
+//avroschema:generate
+type Order struct {
+	ID       string    `json:"id"` // order number
+	Items    []Item    `json:"items"`
+	Coupon   *string   `json:"coupon,omitempty"`
+	PlacedAt time.Time `json:"placed_at"`
+	note     string
+}
+
+type Item struct {
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// LineItem is declared from another struct type.
+type LineItem Item
+
+//avroschema:generate
+type Customer struct {
+	Name  string `json:"name"`
+	Email string
+}
+
+// Status symbols are only known at runtime.
+type Status string
+
+func (Status) AvroEnumSymbols() []string {
+	return strings.Fields("OPEN CLOSED")
+}
+
+type Ticket struct {
+	Status Status `json:"status"`
+}
@@ -0,0 +1,6 @@
+//go:build ignore
+
+// A generator of the package, which is not part of it.
+package main
+
+func main() {}
@@ -0,0 +1,548 @@
+package avroschema
+
+import (
+	"fmt"
+	"go/format"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/*
+GenerateGo generates Go source declaring the types of the given schemas in package pkg,
+e.g. of .avsc files loaded by ParseSchema, so that consumers don't hand-write them.
+
+Records become structs with json, bson and avro tags, enums named string types implementing
+AvroEnum, fixed types named byte arrays and logical types their Reflector counterparts,
+e.g. time.Time or *big.Rat. Nullable unions of a single type become pointers, other unions
+interfaces implemented by their branches. The generated NewAvroReflector function returns a
+Reflector which reflects the types back into the same schemas, as far as Go can express them:
+optional fields always default to null, record docs and aliases are dropped, and so are logical
+types without a Go counterpart.
+
+Schemas which cannot be generated, e.g. with null in the middle of a union, are rejected with
+an *UnsupportedSchemaError.
+*/
+func GenerateGo(pkg string, schemas ...*AvroSchema) ([]byte, error) {
+	g := &goGenerator{
+		declared:    map[string]string{},
+		taken:       map[string]bool{},
+		nameMapping: map[string]string{},
+		namespaces:  map[string]string{},
+		markers:     map[string][]string{},
+		imports:     map[string]bool{},
+	}
+	for _, s := range schemas {
+		g.names = indexSchema(s)
+		resolved, ns, _ := g.names.resolve(s, "")
+		named, ok := resolved.(*AvroSchema)
+		if !ok || !isNamedType(named.Type) {
+			return nil, &UnsupportedSchemaError{Path: "", Reason: "a top-level schema must be a named type"}
+		}
+		if g.namespace == "" {
+			_, g.namespace = fullName(named.Name, named.Namespace, ns)
+		}
+		if _, _, err := g.goType(named, "", lastName(named.Name), lastName(named.Name)); err != nil {
+			return nil, err
+		}
+	}
+
+	src, err := format.Source(g.source(pkg))
+	if err != nil {
+		return nil, fmt.Errorf("avroschema: formatting generated source: %w", err)
+	}
+	return src, nil
+}
+
+type goGenerator struct {
+	names       namedSchemas      // named types of the schema being generated
+	namespace   string            // namespace of the first schema, for the types the Reflector makes up
+	decls       []string          // generated type declarations in order
+	declared    map[string]string // Go type names of the Avro full names declared so far
+	taken       map[string]bool   // Go type names declared so far
+	nameMapping map[string]string // Avro names of Go types whose name differs
+	namespaces  map[string]string // namespaces of Go types
+	unions      []goUnion
+	markers     map[string][]string // marker methods of the union members
+	imports     map[string]bool
+}
+
+type goUnion struct {
+	name     string
+	members  []string
+	nullable bool
+}
+
+/*
+Return the Go type of the schema s at path along with the avro tag options it needs.
+Unions and wrapper types are named after base.
+*/
+func (g *goGenerator) goType(s any, namespace, path, base string) (string, []string, error) {
+	resolved, ns, ok := g.names.resolve(s, namespace)
+	if !ok {
+		return "", nil, &UnsupportedSchemaError{Path: path, Reason: fmt.Sprintf("undefined name %v", s)}
+	}
+
+	switch v := resolved.(type) {
+	case string:
+		return g.primitive(v, path)
+	case []any:
+		name, nullAt, err := g.union(v, ns, path, base)
+		if err != nil {
+			return "", nil, err
+		}
+		if nullAt < 0 {
+			return name, nil, nil
+		}
+		if nullAt > 0 {
+			return "", nil, &UnsupportedSchemaError{Path: path, Reason: "null must lead a union which is not a field type"}
+		}
+		g.setNullable(name)
+		return name, nil, nil
+	}
+
+	schema := resolved.(*AvroSchema)
+	if goType, opts, ok := g.logical(schema); ok {
+		return goType, opts, nil
+	}
+	switch schema.Type {
+	case "record", "error", "enum", "fixed":
+		name, err := g.declare(schema, ns, path)
+		return name, nil, err
+	case "array":
+		items, opts, err := g.goType(schema.Items, ns, path+"[]", base+"Item")
+		return "[]" + items, opts, err
+	case "map":
+		values, opts, err := g.goType(schema.Values, ns, path+"{}", base+"Value")
+		return "map[string]" + values, opts, err
+	}
+	return g.primitive(fmt.Sprint(schema.Type), path)
+}
+
+func (g *goGenerator) primitive(typ, path string) (string, []string, error) {
+	switch typ {
+	case "boolean":
+		return "bool", nil, nil
+	case "int":
+		return "int32", nil, nil
+	case "long":
+		return "int64", nil, nil
+	case "float":
+		return "float32", nil, nil
+	case "double":
+		return "float64", nil, nil
+	case "bytes":
+		return "[]byte", nil, nil
+	case "string":
+		return "string", nil, nil
+	}
+	return "", nil, &UnsupportedSchemaError{Path: path, Reason: fmt.Sprintf("%s has no Go counterpart outside a union", typ)}
+}
+
+/*
+The Go types of logical types the Reflector knows, with the tag options selecting them.
+*/
+func (g *goGenerator) logical(s *AvroSchema) (string, []string, bool) {
+	switch s.LogicalType {
+	case "timestamp-millis":
+		g.imports["time"] = true
+		return "time.Time", nil, true
+	case "timestamp-micros", "timestamp-nanos", "local-timestamp-millis", "local-timestamp-micros", "local-timestamp-nanos":
+		g.imports["time"] = true
+		return "time.Time", []string{"logicalType=" + s.LogicalType}, true
+	case "date":
+		g.imports[avroschemaPath] = true
+		return "avroschema.Date", nil, true
+	case "time-millis":
+		g.imports[avroschemaPath] = true
+		return "avroschema.TimeOfDay", nil, true
+	case "time-micros":
+		g.imports[avroschemaPath] = true
+		return "avroschema.TimeOfDay", []string{"logicalType=time-micros"}, true
+	case "duration":
+		g.imports["time"] = true
+		return "time.Duration", []string{"logicalType=duration"}, true
+	case "uuid":
+		if s.Type == "string" {
+			return "string", []string{"uuid"}, true
+		}
+	case "big-decimal":
+		g.imports["math/big"] = true
+		return "*big.Rat", []string{"big-decimal"}, true
+	case "decimal":
+		g.imports["math/big"] = true
+		opts := []string{fmt.Sprintf("precision=%d", s.Precision)}
+		if s.Scale != 0 {
+			opts = append(opts, fmt.Sprintf("scale=%d", s.Scale))
+		}
+		if s.Type == "fixed" {
+			opts = append([]string{"fixed"}, opts...)
+		}
+		return "*big.Rat", opts, true
+	}
+	return "", nil, false
+}
+
+const avroschemaPath = "github.com/wirelessr/avroschema"
+
+/*
+Declare the Go type of a named Avro type, once per full name.
+*/
+func (g *goGenerator) declare(s *AvroSchema, namespace, path string) (string, error) {
+	full, own := fullName(s.Name, s.Namespace, namespace)
+	if name, ok := g.declared[full]; ok {
+		return name, nil
+	}
+
+	name := goName(lastName(full))
+	if g.taken[name] {
+		return "", &UnsupportedSchemaError{Path: path, Reason: fmt.Sprintf("%s and another type are both named %s in Go", full, name)}
+	}
+	g.taken[name] = true
+	g.declared[full] = name
+	if name != lastName(full) {
+		g.nameMapping[name] = lastName(full)
+	}
+	g.namespaces[name] = own
+
+	var decl strings.Builder
+	writeComment(&decl, s.Doc)
+	switch s.Type {
+	case "enum":
+		g.enum(&decl, s, name)
+	case "fixed":
+		fmt.Fprintf(&decl, "type %s [%d]byte\n", name, s.Size)
+	default:
+		// declared before generating the fields, so that they may refer back to it
+		i := len(g.decls)
+		g.decls = append(g.decls, "")
+		if err := g.record(&decl, s, name, own, path); err != nil {
+			return "", err
+		}
+		g.decls[i] = decl.String()
+		return name, nil
+	}
+	g.decls = append(g.decls, decl.String())
+	return name, nil
+}
+
+func (g *goGenerator) enum(decl *strings.Builder, s *AvroSchema, name string) {
+	fmt.Fprintf(decl, "type %s string\n\nconst (\n", name)
+	for _, sym := range s.Symbols {
+		fmt.Fprintf(decl, "%s %s = %q\n", name+goName(strings.ToLower(sym)), name, sym)
+	}
+	decl.WriteString(")\n\n")
+	fmt.Fprintf(decl, "func (%s) AvroEnumSymbols() []string {\nreturn []string{", name)
+	for i, sym := range s.Symbols {
+		if i > 0 {
+			decl.WriteString(", ")
+		}
+		decl.WriteString(strconv.Quote(sym))
+	}
+	decl.WriteString("}\n}\n")
+	if def, ok := s.Default.(string); ok {
+		fmt.Fprintf(decl, "\nfunc (%s) AvroEnumDefault() string {\nreturn %q\n}\n", name, def)
+	}
+}
+
+func (g *goGenerator) record(decl *strings.Builder, s *AvroSchema, name, namespace, path string) error {
+	fmt.Fprintf(decl, "type %s struct {\n", name)
+	taken := map[string]bool{}
+	for _, f := range s.Fields {
+		fpath := path + "." + f.Name
+		field := goName(f.Name)
+		for taken[field] {
+			field += "_"
+		}
+		taken[field] = true
+
+		typ, opts, err := g.fieldType(f, namespace, fpath, name+field)
+		if err != nil {
+			return err
+		}
+		if f.Doc != "" {
+			opts = append(opts, "doc="+f.Doc)
+		}
+		for _, alias := range f.Aliases {
+			opts = append(opts, "alias="+alias)
+		}
+		if f.Order != "" {
+			opts = append(opts, "order="+f.Order)
+		}
+
+		writeComment(decl, f.Doc)
+		fmt.Fprintf(decl, "%s %s %s\n", field, typ, structTagLiteral(f.Name, opts))
+	}
+	decl.WriteString("}\n")
+	return nil
+}
+
+/*
+The Go type of a record field, nullable unions become optional fields.
+*/
+func (g *goGenerator) fieldType(f *AvroSchema, namespace, path, base string) (string, []string, error) {
+	resolved, ns, _ := g.names.resolve(fieldType(f), namespace)
+	union, isUnion := resolved.([]any)
+	if !isUnion {
+		typ, opts, err := g.goType(fieldType(f), namespace, path, base)
+		if err != nil {
+			return "", nil, err
+		}
+		return typ, withDefault(opts, f.Default), nil
+	}
+
+	nonNull := slices.DeleteFunc(slices.Clone(union), func(b any) bool { return b == "null" })
+	if len(nonNull) == len(union) {
+		typ, _, err := g.goType(union, ns, path, base)
+		return typ, withDefault(nil, f.Default), err
+	}
+	if i := slices.Index(union, any("null")); i != 0 && i != len(union)-1 {
+		return "", nil, &UnsupportedSchemaError{Path: path, Reason: "null must lead or end a union"}
+	}
+
+	// optional fields are nullable with null leading the union, unless their default is not null
+	opts := []string{"optional"}
+	if _, isNull := f.Default.(Null); f.Default != nil && !isNull {
+		opts = withDefault(opts, f.Default)
+	}
+	if len(nonNull) > 1 {
+		name, _, err := g.union(nonNull, ns, path, base)
+		return name, opts, err
+	}
+
+	typ, logicalOpts, err := g.goType(nonNull[0], ns, path, base)
+	if err != nil {
+		return "", nil, err
+	}
+	if !strings.HasPrefix(typ, "*") && !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") && !g.isInterface(typ) {
+		typ = "*" + typ
+	}
+	return typ, append(logicalOpts, opts...), nil
+}
+
+func withDefault(opts []string, def any) []string {
+	if def == nil {
+		return opts
+	}
+	return append(opts, "default="+mustJSON(def))
+}
+
+/*
+Declare an interface implemented by the branches of a union, named base.
+Records, enums and fixed types implement it themselves, other branches through wrapper types.
+Return the position of null in the union, or -1.
+*/
+func (g *goGenerator) union(branches []any, namespace, path, base string) (string, int, error) {
+	nullAt := -1
+	u := goUnion{name: base}
+	for i, b := range branches {
+		if b == "null" {
+			nullAt = i
+			continue
+		}
+		resolved, ns, ok := g.names.resolve(b, namespace)
+		if !ok {
+			return "", 0, &UnsupportedSchemaError{Path: path, Reason: fmt.Sprintf("undefined name %v", b)}
+		}
+
+		if s, ok := resolved.(*AvroSchema); ok && isNamedType(s.Type) && s.LogicalType == "" {
+			member, err := g.declare(s, ns, path)
+			if err != nil {
+				return "", 0, err
+			}
+			u.members = append(u.members, member)
+			continue
+		}
+
+		wrapper := base + goName(schemaKind(resolved))
+		typ, opts, err := g.goType(b, namespace, path, wrapper)
+		if err != nil {
+			return "", 0, err
+		}
+		if len(opts) > 0 || strings.HasPrefix(typ, "avroschema.") || strings.HasPrefix(typ, "time.") {
+			return "", 0, &UnsupportedSchemaError{Path: path, Reason: fmt.Sprintf("logical types of union branches have no Go counterpart, at branch %d", i)}
+		}
+		if g.taken[wrapper] {
+			return "", 0, &UnsupportedSchemaError{Path: path, Reason: fmt.Sprintf("%s is declared already", wrapper)}
+		}
+		g.taken[wrapper] = true
+		g.decls = append(g.decls, fmt.Sprintf("type %s %s\n", wrapper, typ))
+		u.members = append(u.members, wrapper)
+	}
+
+	if g.taken[base] {
+		return "", 0, &UnsupportedSchemaError{Path: path, Reason: fmt.Sprintf("%s is declared already", base)}
+	}
+	g.taken[base] = true
+	for _, member := range u.members {
+		g.markers[member] = append(g.markers[member], "is"+base)
+	}
+	g.decls = append(g.decls, fmt.Sprintf("type %s interface {\nis%s()\n}\n", base, base))
+	g.unions = append(g.unions, u)
+	return base, nullAt, nil
+}
+
+func (g *goGenerator) setNullable(name string) {
+	for i := range g.unions {
+		if g.unions[i].name == name {
+			g.unions[i].nullable = true
+		}
+	}
+}
+
+func (g *goGenerator) isInterface(typ string) bool {
+	return slices.ContainsFunc(g.unions, func(u goUnion) bool { return u.name == typ })
+}
+
+func (g *goGenerator) source(pkg string) []byte {
+	var src strings.Builder
+	fmt.Fprintf(&src, "// Code generated by avroschema. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+
+	var reflector strings.Builder
+	g.reflector(&reflector)
+	g.imports[avroschemaPath] = true
+	// the standard library first, as goimports does
+	var std, other []string
+	for imp := range g.imports {
+		if strings.Contains(imp, ".") {
+			other = append(other, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	src.WriteString("import (\n")
+	for _, imp := range std {
+		fmt.Fprintf(&src, "%q\n", imp)
+	}
+	src.WriteString("\n")
+	for _, imp := range other {
+		fmt.Fprintf(&src, "%q\n", imp)
+	}
+	src.WriteString(")\n\n")
+
+	for _, decl := range g.decls {
+		src.WriteString(decl)
+		src.WriteString("\n")
+	}
+
+	members := make([]string, 0, len(g.markers))
+	for member := range g.markers {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	for _, member := range members {
+		for _, marker := range g.markers[member] {
+			fmt.Fprintf(&src, "func (%s) %s() {}\n", member, marker)
+		}
+	}
+
+	src.WriteString(reflector.String())
+	return []byte(src.String())
+}
+
+/*
+Write NewAvroReflector, configured to reflect the generated types into their schemas.
+*/
+func (g *goGenerator) reflector(src *strings.Builder) {
+	src.WriteString("\n// NewAvroReflector returns a Reflector which reflects the types in this file into the schemas they were generated from.\n")
+	src.WriteString("func NewAvroReflector() *avroschema.Reflector {\nreturn &avroschema.Reflector{\n")
+	fmt.Fprintf(src, "Namespace: %q,\n", g.namespace)
+
+	if len(g.nameMapping) > 0 {
+		src.WriteString("NameMapping: map[string]string{\n")
+		for _, name := range sortedKeys(g.nameMapping) {
+			fmt.Fprintf(src, "%q: %q,\n", name, g.nameMapping[name])
+		}
+		src.WriteString("},\n")
+	}
+
+	if slices.ContainsFunc(sortedKeys(g.namespaces), func(name string) bool { return g.namespaces[name] != g.namespace }) {
+		g.imports["reflect"] = true
+		src.WriteString("Namespacer: func(t reflect.Type) string {\nswitch t {\n")
+		for _, name := range sortedKeys(g.namespaces) {
+			if g.namespaces[name] == g.namespace {
+				continue
+			}
+			fmt.Fprintf(src, "case reflect.TypeOf((*%s)(nil)).Elem():\nreturn %q\n", name, g.namespaces[name])
+		}
+		fmt.Fprintf(src, "}\nreturn %q\n},\n", g.namespace)
+	}
+
+	if len(g.unions) > 0 {
+		g.imports["reflect"] = true
+		src.WriteString("Unions: map[reflect.Type]avroschema.UnionSpec{\n")
+		for _, u := range g.unions {
+			fmt.Fprintf(src, "reflect.TypeOf((*%s)(nil)).Elem(): {\nMembers: []reflect.Type{", u.name)
+			for i, member := range u.members {
+				if i > 0 {
+					src.WriteString(", ")
+				}
+				fmt.Fprintf(src, "reflect.TypeOf((*%s)(nil)).Elem()", member)
+			}
+			src.WriteString("},\n")
+			if u.nullable {
+				src.WriteString("Nullable: true,\n")
+			}
+			src.WriteString("},\n")
+		}
+		src.WriteString("},\n")
+	}
+	src.WriteString("}\n}\n")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/*
+An exported Go identifier for the Avro name, e.g. Order for order and ItemCount for item_count.
+*/
+func goName(name string) string {
+	var ret strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' }) {
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		ret.WriteString(string(runes))
+	}
+	if ret.Len() == 0 || !unicode.IsLetter([]rune(ret.String())[0]) {
+		return "X" + ret.String()
+	}
+	return ret.String()
+}
+
+func writeComment(decl *strings.Builder, doc string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(decl, "// %s\n", line)
+	}
+}
+
+/*
+The struct tag of a generated field, the avro tag carrying the schema options whose commas are escaped.
+*/
+func structTagLiteral(name string, opts []string) string {
+	jsonName := name
+	if slices.Contains(opts, "optional") {
+		jsonName += ",omitempty"
+	}
+	avro := []string{name}
+	for _, opt := range opts {
+		avro = append(avro, strings.ReplaceAll(opt, ",", `\,`))
+	}
+	tag := fmt.Sprintf("json:%q bson:%q avro:%s", jsonName, jsonName, strconv.Quote(strings.Join(avro, ",")))
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
@@ -0,0 +1,46 @@
+package avroschema
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateGo(t *testing.T) {
+	data, err := os.ReadFile("testdata/order.avsc")
+	assert.Nil(t, err)
+	s, err := ParseSchema(data)
+	assert.Nil(t, err)
+
+	src, err := GenerateGo("codegentest", s)
+	assert.Nil(t, err)
+
+	// the generated types are checked to reflect back into the schema in internal/codegentest
+	expected, err := os.ReadFile("internal/codegentest/order.go")
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), string(src))
+}
+
+func TestGenerateGoError(t *testing.T) {
+	tests := []struct {
+		avsc string
+		path string
+	}{
+		{`"string"`, ""},
+		{`{"type": "record", "name": "A", "fields": [{"name": "a", "type": ["string", "null", "int"]}]}`, "A.a"},
+		{`{"type": "record", "name": "A", "fields": [{"name": "a", "type": {"type": "array", "items": ["string", "null"]}}]}`, "A.a[]"},
+		{`{"type": "record", "name": "A", "fields": [{"name": "a", "type": ["string", {"type": "long", "logicalType": "timestamp-millis"}]}]}`, "A.a"},
+		{`{"type": "record", "name": "A", "fields": [{"name": "a", "type": {"type": "map", "values": "null"}}]}`, "A.a{}"},
+		{`{"type": "record", "name": "A", "fields": [{"name": "a", "type": {"type": "record", "name": "a", "fields": []}}]}`, "A.a"},
+	}
+
+	for _, test := range tests {
+		_, err := GenerateGo("x", mustParse(t, test.avsc))
+		var unsupported *UnsupportedSchemaError
+		if assert.True(t, errors.As(err, &unsupported), test.avsc) {
+			assert.Equal(t, test.path, unsupported.Path, test.avsc)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package avroschema
+
+import (
+	"fmt"
+	"slices"
+)
+
+/*
+CompatibilityMode is a schema registry compatibility level, i.e. which versions of a
+schema the new one is checked against and in which direction.
+*/
+type CompatibilityMode int
+
+const (
+	Backward           CompatibilityMode = iota // the new schema reads data written with the latest version
+	BackwardTransitive                          // the new schema reads data written with every version
+	Forward                                     // the latest version reads data written with the new schema
+	ForwardTransitive                           // every version reads data written with the new schema
+	Full                                        // both Backward and Forward
+	FullTransitive                              // both BackwardTransitive and ForwardTransitive
+)
+
+/*
+Incompatibility is a reason why a reader schema cannot read data written with a writer schema.
+
+Path is the field path leading to it, such as `Order.items[].price`, where `[]` marks
+array items and `{}` marks map values. Version is the index into the history of
+CheckHistory the incompatibility was found with, and 0 for CheckCompatibility.
+*/
+type Incompatibility struct {
+	Path    string
+	Message string
+	Version int
+}
+
+func (i Incompatibility) String() string {
+	return fmt.Sprintf("%s: %s", i.Path, i.Message)
+}
+
+/*
+CheckCompatibility reports every reason why reader cannot read data written with writer,
+following the Avro schema resolution rules, or nil if it can.
+*/
+func CheckCompatibility(reader, writer *AvroSchema) []Incompatibility {
+	c := &compatChecker{
+		reader: indexSchema(reader),
+		writer: indexSchema(writer),
+		seen:   map[[2]string]bool{},
+	}
+	path := ""
+	if isNamedType(reader.Type) {
+		path = lastName(reader.Name)
+	}
+	c.check(reader, writer, "", "", path)
+	return c.ret
+}
+
+/*
+CheckHistory checks schema against the versions in history, the oldest first, as required by mode.
+The non-transitive modes only check against the latest version.
+*/
+func CheckHistory(mode CompatibilityMode, schema *AvroSchema, history []*AvroSchema) []Incompatibility {
+	if len(history) == 0 {
+		return nil
+	}
+
+	first := len(history) - 1
+	switch mode {
+	case BackwardTransitive, ForwardTransitive, FullTransitive:
+		first = 0
+	}
+	backward := mode != Forward && mode != ForwardTransitive
+	forward := mode != Backward && mode != BackwardTransitive
+
+	var ret []Incompatibility
+	for version := first; version < len(history); version++ {
+		var found []Incompatibility
+		if backward {
+			found = append(found, CheckCompatibility(schema, history[version])...)
+		}
+		if forward {
+			found = append(found, CheckCompatibility(history[version], schema)...)
+		}
+		for _, i := range found {
+			i.Version = version
+			ret = append(ret, i)
+		}
+	}
+	return ret
+}
+
+type compatChecker struct {
+	reader, writer namedSchemas
+	seen           map[[2]string]bool // pairs of named types checked already, for recursive types
+	ret            []Incompatibility
+}
+
+func (c *compatChecker) report(path, format string, args ...any) {
+	c.ret = append(c.ret, Incompatibility{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+/*
+The type of a resolved schema, records and errors are alike.
+*/
+func resolvedType(s any) any {
+	if schema, ok := s.(*AvroSchema); ok {
+		s = schema.Type
+	}
+	if s == "error" {
+		return "record"
+	}
+	return s
+}
+
+var promotions = map[string][]string{
+	"int":    {"long", "float", "double"},
+	"long":   {"float", "double"},
+	"float":  {"double"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+func (c *compatChecker) check(r, w any, rNs, wNs, path string) {
+	r, rNs, ok := c.reader.resolve(r, rNs)
+	if !ok {
+		c.report(path, "undefined name in the reader")
+		return
+	}
+	w, wNs, ok = c.writer.resolve(w, wNs)
+	if !ok {
+		c.report(path, "undefined name in the writer")
+		return
+	}
+
+	// every branch the writer may have written must be readable
+	if union, ok := resolvedType(w).([]any); ok {
+		for _, b := range union {
+			c.check(r, b, rNs, wNs, path)
+		}
+		return
+	}
+
+	if union, ok := resolvedType(r).([]any); ok {
+		if b, ok := c.selectBranch(union, w, rNs); ok {
+			c.check(b, w, rNs, wNs, path)
+			return
+		}
+		c.report(path, "the writer's %s matches no branch of the reader's union", schemaKind(w))
+		return
+	}
+
+	if !c.matches(r, w, false) {
+		if isNamedType(resolvedType(r)) && resolvedType(r) == resolvedType(w) {
+			c.report(path, "the writer's %s does not match the reader's name or aliases", schemaKind(w))
+		} else {
+			c.report(path, "the writer's %s cannot be read as %s", schemaKind(w), schemaKind(r))
+		}
+		return
+	}
+	c.checkDecimal(r, w, path)
+
+	switch resolvedType(r) {
+	case "record":
+		c.checkRecord(r.(*AvroSchema), w.(*AvroSchema), rNs, wNs, path)
+	case "enum":
+		rs, ws := r.(*AvroSchema), w.(*AvroSchema)
+		for _, sym := range ws.Symbols {
+			if !slices.Contains(rs.Symbols, sym) && rs.Default == nil {
+				c.report(path, "the writer's symbol %s is missing from the reader's enum, which has no default", sym)
+			}
+		}
+	case "fixed":
+		if rs, ws := r.(*AvroSchema), w.(*AvroSchema); rs.Size != ws.Size {
+			c.report(path, "the writer's fixed size %d differs from the reader's size %d", ws.Size, rs.Size)
+		}
+	case "array":
+		c.check(r.(*AvroSchema).Items, w.(*AvroSchema).Items, rNs, wNs, path+"[]")
+	case "map":
+		c.check(r.(*AvroSchema).Values, w.(*AvroSchema).Values, rNs, wNs, path+"{}")
+	}
+}
+
+/*
+Decimals match only if their precisions and scales do, other logical types resolve as their underlying type.
+*/
+func (c *compatChecker) checkDecimal(r, w any, path string) {
+	rs, rok := r.(*AvroSchema)
+	ws, wok := w.(*AvroSchema)
+	if !rok || !wok || rs.LogicalType != "decimal" || ws.LogicalType != "decimal" {
+		return
+	}
+	if rs.Precision != ws.Precision || rs.Scale != ws.Scale {
+		c.report(path, "the writer's decimal(%d,%d) differs from the reader's decimal(%d,%d)", ws.Precision, ws.Scale, rs.Precision, rs.Scale)
+	}
+}
+
+/*
+The first branch of the reader's union matching the writer's schema, preferring a branch of the same type to a promotion.
+*/
+func (c *compatChecker) selectBranch(union []any, w any, rNs string) (any, bool) {
+	for _, exact := range []bool{true, false} {
+		for _, b := range union {
+			if rb, _, ok := c.reader.resolve(b, rNs); ok && c.matches(rb, w, exact) {
+				return b, true
+			}
+		}
+	}
+	return nil, false
+}
+
+/*
+Whether the resolved schemas r and w match, without looking into their fields, items or values.
+Named types match by their unqualified name or the reader's aliases, promotions, e.g. from int to long, unless exact.
+*/
+func (c *compatChecker) matches(r, w any, exact bool) bool {
+	rt, wt := resolvedType(r), resolvedType(w)
+	if rt != wt {
+		if exact {
+			return false
+		}
+		rs, rok := rt.(string)
+		ws, wok := wt.(string)
+		return rok && wok && slices.Contains(promotions[ws], rs)
+	}
+	if !isNamedType(rt) {
+		return true
+	}
+
+	rs, ws := r.(*AvroSchema), w.(*AvroSchema)
+	wName := lastName(ws.Name)
+	if lastName(rs.Name) == wName {
+		return true
+	}
+	for _, alias := range rs.Aliases {
+		if lastName(alias) == wName {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *compatChecker) checkRecord(r, w *AvroSchema, rNs, wNs, path string) {
+	rFull, _ := fullName(r.Name, r.Namespace, rNs)
+	wFull, _ := fullName(w.Name, w.Namespace, wNs)
+	pair := [2]string{rFull, wFull}
+	if c.seen[pair] {
+		return
+	}
+	c.seen[pair] = true
+
+	matched := map[*AvroSchema]bool{}
+	for _, wf := range w.Fields {
+		rf := readerField(r, wf.Name)
+		if rf == nil {
+			// the reader ignores fields it doesn't know
+			continue
+		}
+		matched[rf] = true
+		c.check(fieldType(rf), fieldType(wf), rNs, wNs, path+"."+rf.Name)
+	}
+	for _, rf := range r.Fields {
+		if !matched[rf] && rf.Default == nil {
+			c.report(path+"."+rf.Name, "the reader's field has no default and is missing from the writer")
+		}
+	}
+}
+
+/*
+The field of the reader's record named name, or having it as an alias.
+*/
+func readerField(r *AvroSchema, name string) *AvroSchema {
+	for _, f := range r.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	for _, f := range r.Fields {
+		if slices.Contains(f.Aliases, name) {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package avroschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParse(t *testing.T, avsc string) *AvroSchema {
+	s, err := ParseSchema([]byte(avsc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	tests := []struct {
+		name     string
+		reader   string
+		writer   string
+		expected []Incompatibility
+	}{
+		{
+			"identical",
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": "int"}]}`,
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": "int"}]}`,
+			nil,
+		},
+		{
+			"promotions",
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": "double"}, {"name": "b", "type": "bytes"}, {"name": "c", "type": ["null", "long", "string"]}]}`,
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": "int"}, {"name": "b", "type": "string"}, {"name": "c", "type": "int"}]}`,
+			nil,
+		},
+		{
+			"narrowing",
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": "int"}]}`,
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": "long"}]}`,
+			[]Incompatibility{{Path: "A.a", Message: "the writer's long cannot be read as int"}},
+		},
+		{
+			"added field with and without default, removed field",
+			`{"type": "record", "name": "A", "fields": [{"name": "b", "type": ["null", "int"], "default": null}, {"name": "c", "type": "string"}]}`,
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": "int"}]}`,
+			[]Incompatibility{{Path: "A.c", Message: "the reader's field has no default and is missing from the writer"}},
+		},
+		{
+			"renamed field and record with aliases",
+			`{"type": "record", "name": "B", "aliases": ["com.example.A"], "fields": [{"name": "b", "type": "int", "aliases": ["a"]}]}`,
+			`{"type": "record", "name": "A", "namespace": "com.example", "fields": [{"name": "a", "type": "int"}]}`,
+			nil,
+		},
+		{
+			"renamed record without alias",
+			`{"type": "record", "name": "A", "fields": [{"name": "x", "type": {"type": "record", "name": "C", "fields": []}}]}`,
+			`{"type": "record", "name": "A", "fields": [{"name": "x", "type": {"type": "record", "name": "B", "fields": []}}]}`,
+			[]Incompatibility{{Path: "A.x", Message: "the writer's record B does not match the reader's name or aliases"}},
+		},
+		{
+			"removed enum symbol",
+			`{"type": "record", "name": "A", "fields": [{"name": "e", "type": {"type": "enum", "name": "E", "symbols": ["X"]}}, {"name": "d", "type": {"type": "enum", "name": "D", "symbols": ["X", "U"], "default": "U"}}]}`,
+			`{"type": "record", "name": "A", "fields": [{"name": "e", "type": {"type": "enum", "name": "E", "symbols": ["X", "Y"]}}, {"name": "d", "type": {"type": "enum", "name": "D", "symbols": ["X", "Y"]}}]}`,
+			[]Incompatibility{{Path: "A.e", Message: "the writer's symbol Y is missing from the reader's enum, which has no default"}},
+		},
+		{
+			"fixed size, nested arrays and maps",
+			`{"type": "record", "name": "A", "fields": [{"name": "h", "type": {"type": "map", "values": {"type": "array", "items": {"type": "fixed", "name": "F", "size": 8}}}}]}`,
+			`{"type": "record", "name": "A", "fields": [{"name": "h", "type": {"type": "map", "values": {"type": "array", "items": {"type": "fixed", "name": "F", "size": 16}}}}]}`,
+			[]Incompatibility{{Path: "A.h{}[]", Message: "the writer's fixed size 16 differs from the reader's size 8"}},
+		},
+		{
+			"decimal precision and scale",
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": {"type": "bytes", "logicalType": "decimal", "precision": 12, "scale": 2}}, {"name": "b", "type": {"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": 4}}, {"name": "c", "type": {"type": "fixed", "name": "D", "size": 8, "logicalType": "decimal", "precision": 18}}]}`,
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": {"type": "bytes", "logicalType": "decimal", "precision": 12, "scale": 2}}, {"name": "b", "type": {"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": 2}}, {"name": "c", "type": {"type": "fixed", "name": "D", "size": 8, "logicalType": "decimal", "precision": 16}}]}`,
+			[]Incompatibility{
+				{Path: "A.b", Message: "the writer's decimal(9,2) differs from the reader's decimal(9,4)"},
+				{Path: "A.c", Message: "the writer's decimal(16,0) differs from the reader's decimal(18,0)"},
+			},
+		},
+		{
+			"nullable writer",
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": "string"}]}`,
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": ["null", "string"], "default": null}]}`,
+			[]Incompatibility{{Path: "A.a", Message: "the writer's null cannot be read as string"}},
+		},
+		{
+			"union branch removed",
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": ["null", "string"]}]}`,
+			`{"type": "record", "name": "A", "fields": [{"name": "a", "type": ["null", "string", "boolean"]}]}`,
+			[]Incompatibility{{Path: "A.a", Message: "the writer's boolean matches no branch of the reader's union"}},
+		},
+		{
+			"recursive",
+			`{"type": "record", "name": "Node", "fields": [{"name": "children", "type": {"type": "array", "items": "Node"}}, {"name": "v", "type": "long"}]}`,
+			`{"type": "record", "name": "Node", "fields": [{"name": "children", "type": {"type": "array", "items": "Node"}}, {"name": "v", "type": "int"}]}`,
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		r := CheckCompatibility(mustParse(t, test.reader), mustParse(t, test.writer))
+		assert.Equal(t, test.expected, r, test.name)
+	}
+}
+
+func TestCheckHistory(t *testing.T) {
+	v1 := mustParse(t, `{"type": "record", "name": "A", "fields": [{"name": "a", "type": "int"}]}`)
+	v2 := mustParse(t, `{"type": "record", "name": "A", "fields": [{"name": "a", "type": "int", "default": 0}, {"name": "b", "type": "string", "default": ""}]}`)
+	// drops a, which only v2 has a default for, and adds c without a default
+	v3 := mustParse(t, `{"type": "record", "name": "A", "fields": [{"name": "b", "type": "string", "default": ""}, {"name": "c", "type": "int"}]}`)
+	history := []*AvroSchema{v1, v2}
+
+	assert.Nil(t, CheckHistory(Full, v2, history[:1]))
+	assert.Nil(t, CheckHistory(Backward, v1, nil))
+
+	assert.Equal(t, []Incompatibility{
+		{Path: "A.c", Message: "the reader's field has no default and is missing from the writer", Version: 1},
+	}, CheckHistory(Backward, v3, history))
+
+	assert.Equal(t, []Incompatibility{
+		{Path: "A.c", Message: "the reader's field has no default and is missing from the writer", Version: 0},
+		{Path: "A.c", Message: "the reader's field has no default and is missing from the writer", Version: 1},
+	}, CheckHistory(BackwardTransitive, v3, history))
+
+	assert.Nil(t, CheckHistory(Forward, v3, history))
+	assert.Equal(t, []Incompatibility{
+		{Path: "A.a", Message: "the reader's field has no default and is missing from the writer", Version: 0},
+	}, CheckHistory(ForwardTransitive, v3, history))
+
+	assert.Len(t, CheckHistory(FullTransitive, v3, history), 3)
+	assert.Equal(t, "A.c: the reader's field has no default and is missing from the writer", CheckHistory(Full, v3, history)[0].String())
+}
+
+func TestReflectedCompatibility(t *testing.T) {
+	type Order struct {
+		ID    string `json:"id"`
+		Total int    `json:"total"`
+	}
+	old, err := Reflect(&Order{})
+	assert.Nil(t, err)
+
+	type OrderV2 struct {
+		ID       string  `json:"id"`
+		Total    int64   `json:"total"`
+		Coupon   *string `json:"coupon"`
+		Currency string  `json:"currency"`
+	}
+	reflector := &Reflector{NameMapping: map[string]string{"OrderV2": "Order"}, NullablePointers: true}
+	schema, err := reflector.ReflectSchema(&OrderV2{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []Incompatibility{
+		{Path: "Order.currency", Message: "the reader's field has no default and is missing from the writer"},
+	}, CheckHistory(Backward, schema, []*AvroSchema{mustParse(t, old)}))
+
+	reflector.BeBackwardTransitive = true
+	schema, err = reflector.ReflectSchema(&OrderV2{})
+	assert.Nil(t, err)
+	assert.Nil(t, CheckHistory(BackwardTransitive, schema, []*AvroSchema{mustParse(t, old)}))
+}
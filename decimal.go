@@ -0,0 +1,87 @@
+package avroschema
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+var (
+	bigRatType   = reflect.TypeOf(big.Rat{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+)
+
+/*
+Decimals are bytes, or fixed with the `fixed` tag option, annotated with the decimal logical type.
+Precision and scale come from the tag, falling back to the Reflector defaults.
+Without any precision, BigDecimal selects the Avro 1.12 big-decimal logical type instead.
+Only strings, numbers, big.Rat and big.Float may hold decimals.
+*/
+func (w *walker[T]) handleDecimal(t T, bigNumber bool, tag *avroTag, path string) (any, error) {
+	if !bigNumber && !decimalKind(w.types.Kind(t)) {
+		return nil, &InvalidTagError{Tag: tag.Raw, Path: path, Reason: fmt.Sprintf("decimal does not apply to %s", w.types.String(t))}
+	}
+
+	precision, scale := tag.Precision, tag.Scale
+	if precision == 0 {
+		if tag.BigDecimal || w.BigDecimal {
+			return &AvroSchema{Type: "bytes", LogicalType: "big-decimal"}, nil
+		}
+		precision = w.DecimalPrecision
+	}
+	if !tag.HasScale {
+		scale = w.DecimalScale
+	}
+
+	if precision <= 0 {
+		return nil, &InvalidTagError{Tag: tag.Raw, Path: path, Reason: "decimal precision is not set"}
+	}
+	if scale > precision {
+		return nil, &InvalidTagError{Tag: tag.Raw, Path: path, Reason: fmt.Sprintf("decimal scale %d exceeds precision %d", scale, precision)}
+	}
+
+	if !tag.Fixed {
+		return &AvroSchema{Type: "bytes", LogicalType: "decimal", Precision: precision, Scale: scale}, nil
+	}
+
+	name := fmt.Sprintf("decimal_%d_%d", precision, scale)
+	namespace, ref, err := w.declareSynthetic(bigRatType, name, path)
+	if err != nil || ref != "" {
+		return ref, err
+	}
+
+	return &AvroSchema{
+		Name:        name,
+		Type:        "fixed",
+		Namespace:   namespace,
+		Size:        decimalSize(precision),
+		LogicalType: "decimal",
+		Precision:   precision,
+		Scale:       scale,
+	}, nil
+}
+
+/*
+The smallest fixed size whose two's-complement range holds every unscaled value of the given precision.
+*/
+func decimalSize(precision int) int {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+	size := 1
+	for new(big.Int).Lsh(big.NewInt(1), uint(8*size-1)).Cmp(limit) < 0 {
+		size++
+	}
+	return size
+}
+
+/*
+Whether values of kind may hold a decimal, i.e. strings and numbers.
+*/
+func decimalKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,324 @@
+package avroschema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/*
+ChangeKind is the kind of a Change between two versions of a schema.
+*/
+type ChangeKind int
+
+const (
+	FieldAdded     ChangeKind = iota // New is the type of the field
+	FieldRemoved                     // Old is the type of the field
+	FieldRenamed                     // Old is the former name, matched by an alias of the field
+	TypeRenamed                      // Old and New are the names, matched by an alias of the type
+	TypeChanged                      // Old and New are the types
+	BranchAdded                      // New is the union branch
+	BranchRemoved                    // Old is the union branch
+	SymbolAdded                      // New is the enum symbol
+	SymbolRemoved                    // Old is the enum symbol
+	DefaultChanged                   // Old and New are the defaults as JSON, empty for none
+	DocChanged                       // Old and New are the docs
+)
+
+var changeKindNames = [...]string{
+	FieldAdded:     "field added",
+	FieldRemoved:   "field removed",
+	FieldRenamed:   "field renamed",
+	TypeRenamed:    "type renamed",
+	TypeChanged:    "type changed",
+	BranchAdded:    "union branch added",
+	BranchRemoved:  "union branch removed",
+	SymbolAdded:    "enum symbol added",
+	SymbolRemoved:  "enum symbol removed",
+	DefaultChanged: "default changed",
+	DocChanged:     "doc changed",
+}
+
+func (k ChangeKind) String() string {
+	if k < 0 || int(k) >= len(changeKindNames) {
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+	return changeKindNames[k]
+}
+
+/*
+Change is a difference between two versions of a schema.
+
+Path is the field path it is found at, such as `Order.items[].price`, where `[]` marks
+array items and `{}` marks map values. Types are described briefly, e.g. `array<string>`
+or `[null, record Address]`.
+*/
+type Change struct {
+	Kind ChangeKind
+	Path string
+	Old  string
+	New  string
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case FieldAdded, BranchAdded, SymbolAdded:
+		return fmt.Sprintf("%s: %s %s", c.Path, c.Kind, c.New)
+	case FieldRemoved, BranchRemoved, SymbolRemoved:
+		return fmt.Sprintf("%s: %s %s", c.Path, c.Kind, c.Old)
+	case DocChanged:
+		return fmt.Sprintf("%s: %s from %q to %q", c.Path, c.Kind, c.Old, c.New)
+	}
+	return fmt.Sprintf("%s: %s from %s to %s", c.Path, c.Kind, orNone(c.Old), orNone(c.New))
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+/*
+Diff lists the changes from the old to the new version of a schema, e.g. between the committed .avsc
+and the schema reflected from the Go struct, in the order of the fields. Fields and named types are
+matched by name, or by the aliases of the new version.
+*/
+func Diff(old, new *AvroSchema) []Change {
+	d := &differ{
+		old:  indexSchema(old),
+		new:  indexSchema(new),
+		seen: map[[2]string]bool{},
+	}
+	path := ""
+	if isNamedType(new.Type) {
+		path = lastName(new.Name)
+	}
+	d.diff(old, new, "", "", path)
+	return d.ret
+}
+
+type differ struct {
+	old, new namedSchemas
+	seen     map[[2]string]bool // pairs of named types compared already, for recursive types
+	ret      []Change
+}
+
+func (d *differ) report(kind ChangeKind, path, old, new string) {
+	d.ret = append(d.ret, Change{Kind: kind, Path: path, Old: old, New: new})
+}
+
+func (d *differ) diff(o, n any, oNs, nNs, path string) {
+	o, oNs, oOk := d.old.resolve(o, oNs)
+	n, nNs, nOk := d.new.resolve(n, nNs)
+	if !oOk || !nOk {
+		return
+	}
+
+	oUnion, oIsUnion := resolvedType(o).([]any)
+	nUnion, nIsUnion := resolvedType(n).([]any)
+	if oIsUnion || nIsUnion {
+		// a schema is a union of itself only, e.g. when a field becomes nullable
+		if !oIsUnion {
+			oUnion = []any{o}
+		}
+		if !nIsUnion {
+			nUnion = []any{n}
+		}
+		d.diffUnion(oUnion, nUnion, oNs, nNs, path)
+		return
+	}
+
+	if resolvedType(o) != resolvedType(n) {
+		d.report(TypeChanged, path, describe(o, d.old, oNs), describe(n, d.new, nNs))
+		return
+	}
+
+	switch resolvedType(n) {
+	case "record", "enum", "fixed":
+		d.diffNamed(o.(*AvroSchema), n.(*AvroSchema), oNs, nNs, path)
+	case "array":
+		d.diff(o.(*AvroSchema).Items, n.(*AvroSchema).Items, oNs, nNs, path+"[]")
+	case "map":
+		d.diff(o.(*AvroSchema).Values, n.(*AvroSchema).Values, oNs, nNs, path+"{}")
+	default:
+		// primitives, which may differ in their logical type
+		if od, nd := describe(o, d.old, oNs), describe(n, d.new, nNs); od != nd {
+			d.report(TypeChanged, path, od, nd)
+		}
+	}
+}
+
+/*
+Branches are matched by their type, or name for named types.
+*/
+func (d *differ) diffUnion(o, n []any, oNs, nNs, path string) {
+	key := func(names namedSchemas, b any, ns string) (string, any, string) {
+		r, rNs, _ := names.resolve(b, ns)
+		if s, ok := r.(*AvroSchema); ok && isNamedType(s.Type) {
+			return lastName(s.Name), r, rNs
+		}
+		return fmt.Sprint(resolvedType(r)), r, rNs
+	}
+
+	oldBranches := map[string]bool{}
+	for _, b := range o {
+		k, _, _ := key(d.old, b, oNs)
+		oldBranches[k] = true
+	}
+	newBranches := map[string]bool{}
+	for _, b := range n {
+		k, nb, bNs := key(d.new, b, nNs)
+		newBranches[k] = true
+		if !oldBranches[k] {
+			d.report(BranchAdded, path, "", describe(nb, d.new, bNs))
+		}
+	}
+	for _, b := range o {
+		k, ob, bNs := key(d.old, b, oNs)
+		if !newBranches[k] {
+			d.report(BranchRemoved, path, describe(ob, d.old, bNs), "")
+			continue
+		}
+		for _, nb := range n {
+			if nk, _, _ := key(d.new, nb, nNs); nk == k {
+				d.diff(ob, nb, bNs, nNs, path)
+			}
+		}
+	}
+}
+
+func (d *differ) diffNamed(o, n *AvroSchema, oNs, nNs, path string) {
+	oFull, _ := fullName(o.Name, o.Namespace, oNs)
+	nFull, _ := fullName(n.Name, n.Namespace, nNs)
+	pair := [2]string{oFull, nFull}
+	if d.seen[pair] {
+		return
+	}
+	d.seen[pair] = true
+
+	if oFull != nFull {
+		kind := TypeChanged
+		if slices.Contains(n.Aliases, oFull) || slices.Contains(n.Aliases, lastName(oFull)) {
+			kind = TypeRenamed
+		}
+		d.report(kind, path, oFull, nFull)
+	}
+	if o.Doc != n.Doc {
+		d.report(DocChanged, path, o.Doc, n.Doc)
+	}
+
+	switch n.Type {
+	case "fixed":
+		if o.Size != n.Size {
+			d.report(TypeChanged, path, describe(o, d.old, oNs), describe(n, d.new, nNs))
+		}
+	case "enum":
+		for _, sym := range n.Symbols {
+			if !slices.Contains(o.Symbols, sym) {
+				d.report(SymbolAdded, path, "", sym)
+			}
+		}
+		for _, sym := range o.Symbols {
+			if !slices.Contains(n.Symbols, sym) {
+				d.report(SymbolRemoved, path, sym, "")
+			}
+		}
+		if od, nd := defaultJSON(o.Default), defaultJSON(n.Default); od != nd {
+			d.report(DefaultChanged, path, od, nd)
+		}
+	default:
+		d.diffFields(o, n, oNs, nNs, path)
+	}
+}
+
+func (d *differ) diffFields(o, n *AvroSchema, oNs, nNs, path string) {
+	matched := map[*AvroSchema]bool{}
+	for _, nf := range n.Fields {
+		fpath := path + "." + nf.Name
+		of := oldField(o, nf)
+		if of == nil {
+			d.report(FieldAdded, fpath, "", describe(fieldType(nf), d.new, nNs))
+			continue
+		}
+		matched[of] = true
+		if of.Name != nf.Name {
+			d.report(FieldRenamed, fpath, of.Name, nf.Name)
+		}
+		d.diff(fieldType(of), fieldType(nf), oNs, nNs, fpath)
+		if od, nd := defaultJSON(of.Default), defaultJSON(nf.Default); od != nd {
+			d.report(DefaultChanged, fpath, od, nd)
+		}
+		if of.Doc != nf.Doc {
+			d.report(DocChanged, fpath, of.Doc, nf.Doc)
+		}
+	}
+	for _, of := range o.Fields {
+		if !matched[of] {
+			d.report(FieldRemoved, path+"."+of.Name, describe(fieldType(of), d.old, oNs), "")
+		}
+	}
+}
+
+/*
+The field of the old record named like nf, or by one of its aliases.
+*/
+func oldField(o *AvroSchema, nf *AvroSchema) *AvroSchema {
+	for _, f := range o.Fields {
+		if f.Name == nf.Name {
+			return f
+		}
+	}
+	for _, f := range o.Fields {
+		if slices.Contains(nf.Aliases, f.Name) {
+			return f
+		}
+	}
+	return nil
+}
+
+func defaultJSON(v any) string {
+	if v == nil {
+		return ""
+	}
+	return mustJSON(v)
+}
+
+/*
+A brief description of the type of s, named types are described by name only.
+*/
+func describe(s any, names namedSchemas, namespace string) string {
+	s, namespace, ok := names.resolve(s, namespace)
+	if !ok {
+		return "undefined"
+	}
+	switch v := s.(type) {
+	case string:
+		return v
+	case []any:
+		branches := make([]string, len(v))
+		for i, b := range v {
+			branches[i] = describe(b, names, namespace)
+		}
+		return "[" + strings.Join(branches, ", ") + "]"
+	case *AvroSchema:
+		switch v.Type {
+		case "record", "error", "enum":
+			return fmt.Sprintf("%s %s", v.Type, lastName(v.Name))
+		case "fixed":
+			return fmt.Sprintf("fixed %s(%d)", lastName(v.Name), v.Size)
+		case "array":
+			return "array<" + describe(v.Items, names, namespace) + ">"
+		case "map":
+			return "map<" + describe(v.Values, names, namespace) + ">"
+		}
+		if v.LogicalType == "decimal" {
+			return fmt.Sprintf("%s(decimal %d,%d)", v.Type, v.Precision, v.Scale)
+		}
+		if v.LogicalType != "" {
+			return fmt.Sprintf("%s(%s)", v.Type, v.LogicalType)
+		}
+		return fmt.Sprint(v.Type)
+	}
+	return "unknown"
+}
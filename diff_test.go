@@ -0,0 +1,81 @@
+package avroschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	old := mustParse(t, `{
+    "name": "Order", "type": "record", "doc": "An order",
+    "fields": [
+      {"name": "id", "type": "string"},
+      {"name": "total", "type": "int"},
+      {"name": "note", "type": "string", "default": ""},
+      {"name": "placed", "type": "long", "logicalType": "timestamp-millis"},
+      {"name": "status", "type": {"name": "Status", "type": "enum", "symbols": ["OPEN", "CANCELLED"]}},
+      {"name": "payment", "type": ["null", {"name": "Card", "type": "record", "fields": [{"name": "number", "type": "string"}]}, "string"], "default": null},
+      {"name": "cost", "type": {"type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2}},
+      {"name": "legacy", "type": {"type": "array", "items": "string"}}
+    ]
+  }`)
+	new := mustParse(t, `{
+    "name": "Order", "type": "record", "doc": "A customer order",
+    "fields": [
+      {"name": "id", "type": "string", "doc": "the order id"},
+      {"name": "total", "type": "long"},
+      {"name": "remark", "type": ["null", "string"], "aliases": ["note"], "default": null},
+      {"name": "placed", "type": "long", "logicalType": "timestamp-micros"},
+      {"name": "status", "type": {"name": "Status", "type": "enum", "symbols": ["OPEN", "PAID"], "default": "OPEN"}},
+      {"name": "payment", "type": ["null", {"name": "Card", "type": "record", "fields": [{"name": "number", "type": "string"}, {"name": "expiry", "type": "string", "default": ""}]}, {"name": "Wallet", "type": "record", "fields": []}], "default": null},
+      {"name": "cost", "type": {"type": "bytes", "logicalType": "decimal", "precision": 12, "scale": 2}},
+      {"name": "tags", "type": {"type": "map", "values": {"type": "array", "items": "Status"}}}
+    ]
+  }`)
+
+	expected := []string{
+		`Order: doc changed from "An order" to "A customer order"`,
+		`Order.id: doc changed from "" to "the order id"`,
+		`Order.total: type changed from int to long`,
+		`Order.remark: field renamed from note to remark`,
+		`Order.remark: union branch added null`,
+		`Order.remark: default changed from "" to null`,
+		`Order.placed: type changed from long(timestamp-millis) to long(timestamp-micros)`,
+		`Order.status: enum symbol added PAID`,
+		`Order.status: enum symbol removed CANCELLED`,
+		`Order.status: default changed from none to "OPEN"`,
+		`Order.payment: union branch added record Wallet`,
+		`Order.payment.expiry: field added string`,
+		`Order.payment: union branch removed string`,
+		`Order.cost: type changed from bytes(decimal 10,2) to bytes(decimal 12,2)`,
+		`Order.tags: field added map<array<enum Status>>`,
+		`Order.legacy: field removed array<string>`,
+	}
+
+	var r []string
+	for _, c := range Diff(old, new) {
+		r = append(r, c.String())
+	}
+	assert.Equal(t, expected, r)
+	assert.Nil(t, Diff(old, old))
+}
+
+func TestDiffReflected(t *testing.T) {
+	committed := mustParse(t, `{"name": "Node", "type": "record", "fields": [
+    {"name": "value", "type": "int"},
+    {"name": "children", "type": {"type": "array", "items": "Node"}}
+  ]}`)
+
+	type Node struct {
+		Value    int64   `json:"value"`
+		Children []*Node `json:"children"`
+	}
+	reflected, err := ReflectSchema(&Node{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []Change{
+		{Kind: TypeChanged, Path: "Node.value", Old: "int", New: "long"},
+	}, Diff(committed, reflected))
+	assert.Equal(t, "type changed", TypeChanged.String())
+}
@@ -0,0 +1,39 @@
+package avroschema
+
+import "strings"
+
+/*
+DocRegistry holds the doc comments of Go types and their struct fields, for Reflector.Docs,
+as read from source by static.ParseDocs, e.g. in a go:generate step. Types are keyed by their
+package path and name, such as github.com/acme/billing.Order, fields by the key of the struct
+type declaring them and their Go name, such as github.com/acme/billing.Order.ID. Types of main
+packages have the package path main, as at runtime.
+
+The docs of records, enums and fixed types, and of fields without a `doc` tag option, are
+taken from the registry. Registries of several packages may be merged into one. A Reflector
+tells registries apart by identity when looking up its cache, so a registry must not be changed
+once assigned to Reflector.Docs, assign a changed copy instead.
+*/
+type DocRegistry map[string]string
+
+/*
+The doc of the named type name of the package pkgPath, with the type arguments of generic types ignored.
+*/
+func (d DocRegistry) typeDoc(pkgPath, name string) string {
+	if name == "" {
+		return ""
+	}
+	name, _, _ = strings.Cut(name, "[")
+	return d[pkgPath+"."+name]
+}
+
+/*
+The doc of the field named field of the struct type name of the package pkgPath.
+*/
+func (d DocRegistry) fieldDoc(pkgPath, name, field string) string {
+	if name == "" {
+		return ""
+	}
+	name, _, _ = strings.Cut(name, "[")
+	return d[pkgPath+"."+name+"."+field]
+}
@@ -0,0 +1,66 @@
+package avroschema
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// docOrder is an order.
+type docOrder struct {
+	docCustomer
+	ID     string    `json:"id"`
+	Status docStatus `json:"status" avro:"status,doc=from the tag"`
+	Lines  []docLine `json:"lines"`
+}
+
+type docCustomer struct {
+	Email string `json:"email"`
+}
+
+type docLine struct {
+	SKU string `json:"sku"`
+}
+
+type docStatus string
+
+func (docStatus) AvroEnumSymbols() []string {
+	return []string{"OPEN"}
+}
+
+func TestReflectDocs(t *testing.T) {
+	const pkg = "github.com/wirelessr/avroschema."
+	reflector := &Reflector{Docs: DocRegistry{
+		pkg + "docOrder":          "an order",
+		pkg + "docOrder.ID":       "the order number",
+		pkg + "docOrder.Status":   "from the registry",
+		pkg + "docCustomer.Email": "promoted",
+		pkg + "docStatus":         "the status",
+		pkg + "docLine.SKU":       "stock keeping unit",
+	}}
+
+	schema, err := reflector.ReflectSchema(&docOrder{})
+	assert.Nil(t, err)
+	assert.Equal(t, &AvroSchema{
+		Name: "docOrder",
+		Type: "record",
+		Doc:  "an order",
+		Fields: []*AvroSchema{
+			{Name: "email", Type: "string", Doc: "promoted"},
+			{Name: "id", Type: "string", Doc: "the order number"},
+			{Name: "status", Type: &AvroSchema{Name: "docStatus", Type: "enum", Doc: "the status", Symbols: []string{"OPEN"}}, Doc: "from the tag"},
+			{Name: "lines", Type: &AvroSchema{Type: "array", Items: &AvroSchema{Name: "docLine", Type: "record", Fields: []*AvroSchema{
+				{Name: "sku", Type: "string", Doc: "stock keeping unit"},
+			}}}},
+		},
+	}, schema)
+
+	// the registry is part of the configuration the schema is cached with, by identity
+	docs := maps.Clone(reflector.Docs)
+	docs[pkg+"docOrder"] = "a changed order"
+	reflector.Docs = docs
+	schema, err = reflector.ReflectSchema(&docOrder{})
+	assert.Nil(t, err)
+	assert.Equal(t, "a changed order", schema.Doc)
+}
@@ -0,0 +1,104 @@
+package avroschema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+/*
+AvroEnum is implemented by Go types, usually named string or int types,
+that should be emitted as Avro enums.
+*/
+type AvroEnum interface {
+	AvroEnumSymbols() []string
+}
+
+/*
+AvroEnumDefaulter may be implemented next to AvroEnum to declare the symbol
+readers fall back to when they meet an unknown one.
+*/
+type AvroEnumDefaulter interface {
+	AvroEnumDefault() string
+}
+
+/*
+EnumSpec declares the symbols of a Go type registered in Reflector.Enums,
+for types which cannot implement AvroEnum themselves.
+*/
+type EnumSpec struct {
+	Symbols []string
+	Default string
+}
+
+var (
+	avroEnumType          = reflect.TypeOf((*AvroEnum)(nil)).Elem()
+	avroEnumDefaulterType = reflect.TypeOf((*AvroEnumDefaulter)(nil)).Elem()
+)
+
+/*
+Look up the enum declaration of t, either registered or through AvroEnum, nil if t is no enum.
+*/
+func (w *walker[T]) enumSpec(t T, path string) (*EnumSpec, error) {
+	if rt, ok := w.native(t); ok {
+		if spec, ok := w.Enums[rt]; ok {
+			return &spec, nil
+		}
+	}
+
+	spec, err := w.types.Enum(t)
+	if err != nil {
+		return nil, w.typeError(t, path, err.Error())
+	}
+	return spec, nil
+}
+
+/*
+Return type is either the enum *AvroSchema or, if already defined, its full name.
+*/
+func (w *walker[T]) handleEnum(t T, spec *EnumSpec, path string) (any, error) {
+	if err := validateEnum(spec); err != nil {
+		if rt, ok := w.native(t); ok {
+			return nil, &InvalidEnumError{Type: rt, Path: path, Reason: err.Error()}
+		}
+		return nil, w.typeError(t, path, "invalid enum: "+err.Error())
+	}
+
+	name, namespace, ref, err := w.declareType(t, path)
+	if err != nil || ref != "" {
+		return ref, err
+	}
+
+	ret := &AvroSchema{
+		Name:      name,
+		Type:      "enum",
+		Namespace: namespace,
+		Doc:       w.typeDoc(t),
+		Symbols:   spec.Symbols,
+	}
+	if spec.Default != "" {
+		ret.Default = spec.Default
+	}
+	return ret, nil
+}
+
+func validateEnum(spec *EnumSpec) error {
+	if len(spec.Symbols) == 0 {
+		return fmt.Errorf("no symbols")
+	}
+
+	seen := make(map[string]bool, len(spec.Symbols))
+	for _, symbol := range spec.Symbols {
+		if !isValidName(symbol) {
+			return fmt.Errorf("invalid symbol %q", symbol)
+		}
+		if seen[symbol] {
+			return fmt.Errorf("duplicate symbol %q", symbol)
+		}
+		seen[symbol] = true
+	}
+
+	if spec.Default != "" && !seen[spec.Default] {
+		return fmt.Errorf("default %q is not a symbol", spec.Default)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package avroschema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+/*
+UnsupportedTypeError is returned by a strict Reflector when a Go type has no
+faithful Avro mapping, e.g. chan, func, complex or interface kinds.
+
+Path is the field path leading to the type, such as `Order.items[].price`,
+where `[]` marks array items and `{}` marks map values.
+*/
+type UnsupportedTypeError struct {
+	Type reflect.Type
+	Path string
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("avroschema: unsupported type %s at %s", e.Type, e.Path)
+}
+
+/*
+InvalidRootError is returned when the value to reflect is neither a struct nor a pointer to one.
+Type is nil for a nil value.
+*/
+type InvalidRootError struct {
+	Type reflect.Type
+}
+
+func (e *InvalidRootError) Error() string {
+	if e.Type == nil {
+		return "avroschema: cannot reflect nil, a struct is expected"
+	}
+	return fmt.Sprintf("avroschema: cannot reflect %s, a struct is expected", e.Type)
+}
+
+/*
+InvalidMapperResultError is returned by a strict Reflector when its Mapper
+returns a value that is neither a string, an *AvroSchema, a []*AvroSchema nor
+a union, i.e. a []any.
+*/
+type InvalidMapperResultError struct {
+	Type   reflect.Type
+	Path   string
+	Result any
+}
+
+func (e *InvalidMapperResultError) Error() string {
+	return fmt.Sprintf("avroschema: mapper returned %T for type %s at %s", e.Result, e.Type, e.Path)
+}
+
+/*
+InvalidTagError is returned when an `avro` struct tag cannot be parsed or
+does not fit the field it is attached to.
+*/
+type InvalidTagError struct {
+	Tag    string
+	Path   string
+	Reason string
+}
+
+func (e *InvalidTagError) Error() string {
+	if e.Tag == "" {
+		return fmt.Sprintf("avroschema: invalid field at %s: %s", e.Path, e.Reason)
+	}
+	return fmt.Sprintf("avroschema: invalid tag `avro:%q` at %s: %s", e.Tag, e.Path, e.Reason)
+}
+
+/*
+InvalidEnumError is returned when the symbols declared for an enum type
+violate the Avro specification.
+*/
+type InvalidEnumError struct {
+	Type   reflect.Type
+	Path   string
+	Reason string
+}
+
+func (e *InvalidEnumError) Error() string {
+	return fmt.Sprintf("avroschema: invalid enum %s at %s: %s", e.Type, e.Path, e.Reason)
+}
+
+/*
+NameCollisionError is returned when two distinct Go types map to the same
+Avro full name, e.g. billing.Address and shipping.Address without a Namespacer.
+*/
+type NameCollisionError struct {
+	Name     string
+	Type     reflect.Type
+	Previous reflect.Type
+	Path     string
+}
+
+func (e *NameCollisionError) Error() string {
+	return fmt.Sprintf("avroschema: %s at %s and %s share the name %s", e.Type, e.Path, e.Previous, e.Name)
+}
+
+/*
+InvalidNameError is returned when a record, enum, fixed or field name, or a
+namespace, does not follow the Avro naming rules, i.e. [A-Za-z_][A-Za-z0-9_]*.
+*/
+type InvalidNameError struct {
+	Name string
+	Path string
+}
+
+func (e *InvalidNameError) Error() string {
+	return fmt.Sprintf("avroschema: invalid name %q at %s", e.Name, e.Path)
+}
+
+/*
+InvalidUnionError is returned when the members registered for an interface
+type do not make a valid Avro union.
+*/
+type InvalidUnionError struct {
+	Type   reflect.Type
+	Path   string
+	Reason string
+}
+
+func (e *InvalidUnionError) Error() string {
+	return fmt.Sprintf("avroschema: invalid union %s at %s: %s", e.Type, e.Path, e.Reason)
+}
+
+/*
+ParseError is returned by ParseSchema when the schema JSON is malformed or
+violates the Avro specification.
+
+Path is a JSON path to the offending value, such as `$.fields[2].type`.
+*/
+type ParseError struct {
+	Path   string
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("avroschema: invalid schema at %s: %s", e.Path, e.Reason)
+}
+
+/*
+UnsupportedSchemaError is returned by GenerateGo for schemas without a Go
+counterpart the Reflector maps back to them, e.g. a union with null in the middle.
+*/
+type UnsupportedSchemaError struct {
+	Path   string
+	Reason string
+}
+
+func (e *UnsupportedSchemaError) Error() string {
+	return fmt.Sprintf("avroschema: cannot generate Go for %s: %s", e.Path, e.Reason)
+}
+
+/*
+StaticTypeError is returned by ReflectWith, e.g. through package static, in place of an UnsupportedTypeError,
+InvalidEnumError or NameCollisionError for types other than reflect.Type, as there is no reflect.Type to report.
+Type is the Go type qualified by its package path, e.g. github.com/acme/billing.Status.
+*/
+type StaticTypeError struct {
+	Type   string
+	Path   string
+	Reason string
+}
+
+func (e *StaticTypeError) Error() string {
+	return fmt.Sprintf("avroschema: %s at %s: %s", e.Type, e.Path, e.Reason)
+}
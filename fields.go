@@ -0,0 +1,268 @@
+package avroschema
+
+import (
+	"reflect"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+/*
+A struct field to be emitted, possibly promoted from an embedded struct.
+T is the type of the TypeSystem walked, e.g. a reflect.Type.
+*/
+type structField[T comparable] struct {
+	index  []int
+	typ    T
+	tag    *avroTag
+	tagged bool   // the name comes from a tag
+	named  bool   // carries an avro tag or a json/bson tag name
+	owner  T      // the struct type declaring the field, for its doc
+	goName string // the name of the field in Go
+}
+
+/*
+A field of a struct type as declared, before applying the rules of encoding/json.
+*/
+type goField[T comparable] struct {
+	name     string
+	exported bool
+	embedded bool
+	tag      reflect.StructTag
+	typ      T
+	elem     T            // typ without an unnamed pointer
+	kind     reflect.Kind // of elem
+	baseKind reflect.Kind // of typ without any pointers
+}
+
+/*
+The declared fields of the struct type t.
+*/
+func (w *walker[T]) fields(t T) []goField[T] {
+	fields := w.types.Fields(t)
+	ret := make([]goField[T], len(fields))
+	for i, f := range fields {
+		elem := f.Type
+		if w.types.Name(elem) == "" && w.types.Kind(elem) == reflect.Ptr {
+			elem = w.types.Elem(elem)
+		}
+		base := f.Type
+		for w.types.Kind(base) == reflect.Ptr {
+			base = w.types.Elem(base)
+		}
+		ret[i] = goField[T]{
+			name:     f.Name,
+			exported: isExported(f.Name),
+			embedded: f.Embedded,
+			tag:      f.Tag,
+			typ:      f.Type,
+			elem:     elem,
+			kind:     w.types.Kind(elem),
+			baseKind: w.types.Kind(base),
+		}
+	}
+	return ret
+}
+
+func isExported(name string) bool {
+	c, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(c)
+}
+
+/*
+Collect the fields of t following the rules of encoding/json: unexported fields
+are ignored, untagged embedded structs (and pointers to them) have their fields
+promoted, as do fields marked `inline`, and among fields of the same name the
+shallowest wins, then the tagged one, while any other conflict drops them all.
+*/
+func (w *walker[T]) collectFields(t T, path string) ([]structField[T], error) {
+	type embedded struct {
+		typ   T
+		index []int
+	}
+	current := []embedded{}
+	next := []embedded{{typ: t}}
+
+	// types explored at the current and next level
+	var count, nextCount map[T]int
+	visited := map[T]bool{}
+
+	var fields []structField[T]
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[T]int{}
+
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+
+			for i, sf := range w.fields(e.typ) {
+				if sf.embedded {
+					// the exported fields of unexported embedded structs are still promoted
+					if !sf.exported && sf.kind != reflect.Struct {
+						continue
+					}
+				} else if !sf.exported {
+					continue
+				}
+
+				tag, tagged, named, err := w.fieldTag(sf.name, sf.tag, sf.kind, sf.baseKind)
+				if err != nil {
+					return nil, &InvalidTagError{Tag: sf.tag.Get("avro"), Path: path + "." + sf.name, Reason: err.Error()}
+				}
+				if tag == nil {
+					continue
+				}
+
+				index := make([]int, len(e.index)+1)
+				copy(index, e.index)
+				index[len(e.index)] = i
+
+				if !promoted(sf.tag, sf.embedded, sf.kind, tagged) {
+					f := structField[T]{index: index, typ: sf.typ, tag: tag, tagged: tagged, named: named, owner: e.typ, goName: sf.name}
+					fields = append(fields, f)
+					if count[e.typ] > 1 {
+						// If there were multiple instances, add a second,
+						// so that the annihilation code will see a duplicate.
+						fields = append(fields, f)
+					}
+					continue
+				}
+
+				nextCount[sf.elem]++
+				if nextCount[sf.elem] == 1 {
+					next = append(next, embedded{typ: sf.elem, index: index})
+				}
+			}
+		}
+	}
+	return dominantFields(fields, w.EmitAllFields), nil
+}
+
+/*
+Keep the dominant field of each name, dropping conflicting ones, in the order of their index sequence.
+Unless emitting all fields, fields with no avro tag or json/bson tag names are ignored.
+*/
+func dominantFields[T comparable](fields []structField[T], emitAllFields bool) []structField[T] {
+	slices.SortFunc(fields, compareFields[T])
+
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		name := fields[i].tag.Name
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].tag.Name != name {
+				break
+			}
+		}
+		if advance > 1 && len(fields[i].index) == len(fields[i+1].index) && fields[i].tagged == fields[i+1].tagged {
+			continue
+		}
+		if !emitAllFields && !fields[i].named {
+			continue
+		}
+		out = append(out, fields[i])
+	}
+
+	slices.SortFunc(out, func(a, b structField[T]) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return out
+}
+
+/*
+Order fields by name, breaking ties with depth, then tagged fields first, then index sequence.
+*/
+func compareFields[T comparable](a, b structField[T]) int {
+	if a.tag.Name != b.tag.Name {
+		if a.tag.Name < b.tag.Name {
+			return -1
+		}
+		return 1
+	}
+	if len(a.index) != len(b.index) {
+		return len(a.index) - len(b.index)
+	}
+	if a.tagged != b.tagged {
+		if a.tagged {
+			return -1
+		}
+		return 1
+	}
+	return slices.Compare(a.index, b.index)
+}
+
+/*
+Whether the fields of a struct field, of the given kind, are promoted to its parent,
+i.e. it is embedded without a tag name or tagged inline.
+*/
+func promoted(st reflect.StructTag, embedded bool, kind reflect.Kind, tagged bool) bool {
+	if kind != reflect.Struct {
+		return false
+	}
+	if parseStructTag(st.Get("json")).Inline || parseStructTag(st.Get("bson")).Inline {
+		return true
+	}
+	return embedded && !tagged
+}
+
+/*
+Resolve the Avro name and options of a struct field, or nil if the field is skipped.
+An `avro` tag takes precedence over json/bson tags. Tagged reports whether the name
+comes from a tag, named whether the field is tagged at all. Kind is the kind of the field's
+type without an unnamed pointer, baseKind without any pointers.
+*/
+func (r *Reflector) fieldTag(name string, st reflect.StructTag, kind, baseKind reflect.Kind) (tag *avroTag, tagged, named bool, err error) {
+	jStructTag := parseStructTag(st.Get("json"))
+	bStructTag := parseStructTag(st.Get("bson"))
+
+	avroTagStr, hasAvroTag := st.Lookup("avro")
+	tag = &avroTag{}
+	if hasAvroTag {
+		if tag, err = parseAvroTag(avroTagStr); err != nil {
+			return nil, false, false, err
+		}
+		if tag.Skip {
+			return nil, false, false, nil
+		}
+		if tag.HasDefault {
+			if tag.Default, err = decodeDefault(tag.RawDefault, baseKind); err != nil {
+				return nil, false, false, err
+			}
+		}
+	} else {
+		if jStructTag.Skip || bStructTag.Skip {
+			return nil, false, false, nil
+		}
+		// This is likely a backwards compatilbity break with whatever the mgm stuff is, as ObjectID is marked optional in bson, not in json.
+		// previously bson's optional was never considered here.
+		tag.Optional = jStructTag.Optional || bStructTag.Optional
+	}
+	named = hasAvroTag || jStructTag.Name != "" || bStructTag.Name != ""
+
+	// prefer bson tag name in attempt at more compatability with this MgmExtension thing, the mapper for which mimics the bson naming
+	if tag.Name == "" {
+		if bStructTag.Name != "" {
+			tag.Name = bStructTag.Name
+		} else {
+			tag.Name = jStructTag.Name
+		}
+	}
+	tagged = tag.Name != ""
+
+	if !tagged || r.SkipTagFieldNames {
+		// otherwise must be emitting all fields or tagged without a name, so no other choice than to take the go name
+		tag.Name = name
+	}
+
+	// encoding/json only quotes scalars
+	switch kind {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		tag.Quoted = jStructTag.Quoted
+	}
+	return tag, tagged, named, nil
+}
@@ -0,0 +1,5 @@
+/*
+Package codegentest holds the Go types generated from testdata/order.avsc, to
+check that they reflect back into the same schema.
+*/
+package codegentest
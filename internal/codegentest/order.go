@@ -0,0 +1,104 @@
+// Code generated by avroschema. DO NOT EDIT.
+
+package codegentest
+
+import (
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/wirelessr/avroschema"
+)
+
+type Order struct {
+	Id       string            `json:"id" bson:"id" avro:"id,uuid"`
+	Status   Status            `json:"status" bson:"status" avro:"status,default=\"OPEN\""`
+	Hash     Md5               `json:"hash" bson:"hash" avro:"hash"`
+	Amount   *big.Rat          `json:"amount" bson:"amount" avro:"amount,precision=10,scale=2"`
+	PlacedAt time.Time         `json:"placed_at" bson:"placed_at" avro:"placed_at,logicalType=timestamp-micros"`
+	ShipBy   *avroschema.Date  `json:"ship_by,omitempty" bson:"ship_by,omitempty" avro:"ship_by,optional"`
+	Items    []Item            `json:"items" bson:"items" avro:"items"`
+	Labels   map[string]string `json:"labels" bson:"labels" avro:"labels,default={}"`
+	Note     *string           `json:"note,omitempty" bson:"note,omitempty" avro:"note,optional"`
+	Priority *int64            `json:"priority,omitempty" bson:"priority,omitempty" avro:"priority,optional,default=0"`
+	Payment  OrderPayment      `json:"payment" bson:"payment" avro:"payment"`
+	Parent   *Order            `json:"parent,omitempty" bson:"parent,omitempty" avro:"parent,optional"`
+	Scores   []OrderScoresItem `json:"scores" bson:"scores" avro:"scores,order=descending"`
+}
+
+type Status string
+
+const (
+	StatusOpen       Status = "OPEN"
+	StatusInProgress Status = "IN_PROGRESS"
+	StatusPaid       Status = "PAID"
+)
+
+func (Status) AvroEnumSymbols() []string {
+	return []string{"OPEN", "IN_PROGRESS", "PAID"}
+}
+
+func (Status) AvroEnumDefault() string {
+	return "OPEN"
+}
+
+type Md5 [16]byte
+
+type Item struct {
+	// stock keeping unit, e.g. "A-1"
+	Sku      string `json:"sku" bson:"sku" avro:"sku,doc=stock keeping unit\\, e.g. \"A-1\""`
+	Quantity int32  `json:"quantity" bson:"quantity" avro:"quantity,default=1"`
+}
+
+type Card struct {
+	Number string `json:"number" bson:"number" avro:"number"`
+}
+
+type Wallet struct {
+	Provider string `json:"provider" bson:"provider" avro:"provider,alias=vendor"`
+}
+
+type OrderPaymentString string
+
+type OrderPayment interface {
+	isOrderPayment()
+}
+
+type OrderScoresItemDouble float64
+
+type OrderScoresItem interface {
+	isOrderScoresItem()
+}
+
+func (Card) isOrderPayment()                     {}
+func (OrderPaymentString) isOrderPayment()       {}
+func (OrderScoresItemDouble) isOrderScoresItem() {}
+func (Wallet) isOrderPayment()                   {}
+
+// NewAvroReflector returns a Reflector which reflects the types in this file into the schemas they were generated from.
+func NewAvroReflector() *avroschema.Reflector {
+	return &avroschema.Reflector{
+		Namespace: "com.example.orders",
+		NameMapping: map[string]string{
+			"Md5": "md5",
+		},
+		Namespacer: func(t reflect.Type) string {
+			switch t {
+			case reflect.TypeOf((*Card)(nil)).Elem():
+				return "com.example.payments"
+			case reflect.TypeOf((*Wallet)(nil)).Elem():
+				return "com.example.payments"
+			}
+			return "com.example.orders"
+		},
+		Unions: map[reflect.Type]avroschema.UnionSpec{
+			reflect.TypeOf((*OrderPayment)(nil)).Elem(): {
+				Members: []reflect.Type{reflect.TypeOf((*Card)(nil)).Elem(), reflect.TypeOf((*Wallet)(nil)).Elem(), reflect.TypeOf((*OrderPaymentString)(nil)).Elem()},
+			},
+			reflect.TypeOf((*OrderScoresItem)(nil)).Elem(): {
+				Members:  []reflect.Type{reflect.TypeOf((*OrderScoresItemDouble)(nil)).Elem()},
+				Nullable: true,
+			},
+		},
+	}
+}
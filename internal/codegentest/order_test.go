@@ -0,0 +1,27 @@
+package codegentest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wirelessr/avroschema"
+)
+
+func TestRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/order.avsc")
+	assert.Nil(t, err)
+	expected, err := avroschema.ParseSchema(data)
+	assert.Nil(t, err)
+
+	reflected, err := NewAvroReflector().ReflectSchema(&Order{})
+	assert.Nil(t, err)
+
+	assert.Nil(t, avroschema.Diff(expected, reflected))
+
+	expectedForm, err := avroschema.CanonicalForm(expected)
+	assert.Nil(t, err)
+	reflectedForm, err := avroschema.CanonicalForm(reflected)
+	assert.Nil(t, err)
+	assert.Equal(t, expectedForm, reflectedForm)
+}
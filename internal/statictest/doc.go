@@ -0,0 +1,5 @@
+/*
+Package statictest holds Go types exercising the mapping rules, to check that
+package static reflects them from source into the same schemas as ReflectSchema.
+*/
+package statictest
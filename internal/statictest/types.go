@@ -0,0 +1,118 @@
+package statictest
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/wirelessr/avroschema"
+)
+
+// Order is placed by a customer.
+type Order struct {
+	// ID is random.
+	ID         UUID               `json:"id"`
+	Ref        string             `json:"ref" avro:"ref,uuid,doc=external reference"`
+	Status     Status             `json:"status"`
+	Priority   Priority           `json:"priority,omitempty"`
+	Level      Level              `json:"level"`
+	Amount     *big.Rat           `json:"amount" avro:"amount,precision=10,scale=2"`
+	Fee        string             `json:"fee" avro:"fee,decimal,precision=6,scale=2,fixed"`
+	PlacedAt   time.Time          `json:"placed_at"`
+	ShipBy     *avroschema.Date   `json:"ship_by,omitempty"`
+	Window     time.Duration      `json:"window"`
+	Stamp      int64              `json:"stamp" avro:"stamp,logicalType=timestamp-micros"`
+	Hash       [16]byte           `json:"hash"`
+	Checksum   Checksum           `json:"checksum"`
+	Items      []Item             `json:"items"`
+	Labels     map[string]string  `json:"labels" avro:"labels,default={}"`
+	Counts     map[int]int64      `json:"counts"`
+	Raw        []byte             `json:"raw"`
+	Meta       struct{ A string } `json:"meta"`
+	Parent     *Order             `json:"parent,omitempty"`
+	Page       Page[Item]         `json:"page"`
+	Any        any                `json:"any"`
+	Quoted     int                `json:"quoted,string"`
+	Ratio      float32            `json:"ratio" avro:"ratio,default=0.5,order=descending,alias=rate"`
+	Flags      []bool             `bson:"flags"`
+	Skipped    string             `json:"-"`
+	Untagged   string
+	Alias      Text           `json:"alias"`
+	Customer                  // promoted
+	*Audit     `json:"audit"` // tagged, so not promoted
+	ByID       map[UUID]Item  `json:"by_id"`
+	unexported string
+}
+
+type Customer struct {
+	Name  string `json:"name"` // full name
+	Email string `json:"email,omitempty"`
+}
+
+type Audit struct {
+	By string `json:"by"`
+}
+
+// Item is a line of an order.
+type Item struct {
+	SKU      string `json:"sku"`
+	Quantity uint16 `json:"quantity" avro:"quantity,default=1"`
+}
+
+// Page of entries.
+type Page[T any] struct {
+	Entries []T     `json:"entries"`
+	Next    *string `json:"next"`
+}
+
+type Text = string
+
+// Checksum is CRC-64.
+type Checksum [8]byte
+
+/*
+UUID is like github.com/google/uuid.UUID.
+*/
+type UUID [16]byte
+
+func (u UUID) MarshalText() ([]byte, error) {
+	return u[:], nil
+}
+
+// Status of an order.
+type Status string
+
+const (
+	StatusOpen Status = "OPEN"
+	StatusPaid Status = "PAID"
+)
+
+func (Status) AvroEnumSymbols() []string {
+	return []string{string(StatusOpen), string(StatusPaid)}
+}
+
+func (Status) AvroEnumDefault() string {
+	return string(StatusOpen)
+}
+
+type Priority int
+
+var prioritySymbols = []string{"LOW", "HIGH"}
+
+func (*Priority) AvroEnumSymbols() []string {
+	return prioritySymbols
+}
+
+type Level string
+
+const (
+	LevelDebug Level = "DEBUG"
+	LevelInfo  Level = "INFO"
+)
+
+func (Level) AvroEnumSymbols() []string {
+	ret := []string{}
+	for _, l := range []Level{LevelDebug, LevelInfo} {
+		ret = append(ret, string(l))
+	}
+	return ret
+}
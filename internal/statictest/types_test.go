@@ -0,0 +1,71 @@
+package statictest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wirelessr/avroschema"
+	"github.com/wirelessr/avroschema/static"
+)
+
+func TestReflectStatic(t *testing.T) {
+	pkg, err := static.Load(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := static.ParseDocs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		reflector func() *avroschema.Reflector
+	}{
+		{"defaults", func() *avroschema.Reflector { return &avroschema.Reflector{} }},
+		{"docs", func() *avroschema.Reflector { return &avroschema.Reflector{Docs: docs, UUIDAsFixed: true} }},
+		{"options", func() *avroschema.Reflector {
+			return &avroschema.Reflector{
+				Namespace:            "com.example",
+				EmitAllFields:        true,
+				BeBackwardTransitive: true,
+				NameMapping:          map[string]string{"Item": "LineItem"},
+				MapKeys:              avroschema.MapKeysEntries,
+				TimePrecision:        avroschema.Micros,
+				DurationAsFixed:      true,
+				UUIDAsFixed:          true,
+			}
+		}},
+		{"nullable pointers and text keys", func() *avroschema.Reflector {
+			return &avroschema.Reflector{
+				NullablePointers:  true,
+				SkipTagFieldNames: true,
+				MapKeys:           avroschema.MapKeysText,
+				LocalTimestamps:   true,
+			}
+		}},
+	}
+
+	for _, test := range tests {
+		expected, err := test.reflector().ReflectSchema(&Order{})
+		assert.Nil(t, err, test.name)
+		actual, err := static.Reflect(test.reflector(), pkg, "Order")
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, expected, actual, test.name)
+	}
+}
+
+func TestReflectStaticIgnoresMapper(t *testing.T) {
+	pkg, err := static.Load(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// not even for the types the Reflector maps specially, e.g. time.Time
+	expected, err := (&avroschema.Reflector{}).ReflectSchema(&Order{})
+	assert.Nil(t, err)
+	actual, err := static.Reflect(&avroschema.Reflector{Mapper: func(reflect.Type) any { return "string" }}, pkg, "Order")
+	assert.Nil(t, err)
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,83 @@
+package avroschema
+
+import "reflect"
+
+/*
+MapKeyMode selects how maps with non-string keys are emitted, as Avro maps only have string keys.
+*/
+type MapKeyMode int
+
+const (
+	// MapKeysFallback emits the whole map as "string", or fails in strict mode.
+	MapKeysFallback MapKeyMode = iota
+	// MapKeysEntries emits an array of records with a typed key and a value field.
+	MapKeysEntries
+	// MapKeysText keeps the Avro map for keys that encoding/json stringifies,
+	// i.e. integers and types implementing encoding.TextMarshaler.
+	MapKeysText
+)
+
+func (w *walker[T]) handleNonStringMap(t T, path string, tag *avroTag) (any, error) {
+	switch w.MapKeys {
+	case MapKeysEntries:
+		return w.handleMapEntries(t, path, tag)
+	case MapKeysText:
+		if w.isTextKey(w.types.Key(t)) {
+			return w.handleMap(t, path, tag)
+		}
+	}
+	// If the key is not a string, then treat the whole object as a string.
+	return w.fallback(t, path)
+}
+
+func (w *walker[T]) isTextKey(t T) bool {
+	if w.types.MarshalsText(t) {
+		return true
+	}
+	switch w.types.Kind(t) {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+/*
+Return type is the array of entry records, named after the map type or the field path.
+*/
+func (w *walker[T]) handleMapEntries(t T, path string, tag *avroTag) (any, error) {
+	path += "[]"
+	name, namespace, ref, err := w.declareType(t, path)
+	if err != nil {
+		return nil, err
+	}
+	if ref != "" {
+		return &AvroSchema{Type: "array", Items: ref}, nil
+	}
+
+	enclosing := w.enclosing
+	w.enclosing = namespace
+	defer func() { w.enclosing = enclosing }()
+
+	key, err := w.reflectType(w.types.Key(t), path+".key", &avroTag{})
+	if err != nil {
+		return nil, err
+	}
+	value, err := w.reflectType(w.types.Elem(t), path+".value", tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AvroSchema{
+		Type: "array",
+		Items: &AvroSchema{
+			Name:      name,
+			Type:      "record",
+			Namespace: namespace,
+			Fields: []*AvroSchema{
+				{Name: "key", Type: key},
+				{Name: "value", Type: value},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,228 @@
+package avroschema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+/*
+Resolve the name and namespace of the Go named type t. If t has been defined
+already, ref holds the full name to refer to it by instead.
+*/
+func (w *walker[T]) declareType(t T, path string) (name, namespace, ref string, err error) {
+	if fullname, ok := w.defined[t]; ok {
+		return "", "", fullname, nil
+	}
+
+	name = w.typeName(t, path)
+	if !isValidName(name) {
+		return "", "", "", &InvalidNameError{Name: name, Path: path}
+	}
+
+	namespace = w.Namespace
+	if rt, ok := w.native(t); ok && w.Namespacer != nil {
+		namespace = w.Namespacer(rt)
+	}
+	// types without a namespace of their own, e.g. anonymous structs, live in the enclosing one,
+	// stated explicitly, so that references by full name resolve from other namespaces as well
+	if namespace == "" {
+		namespace = w.enclosing
+	}
+	if !isValidNamespace(namespace) {
+		return "", "", "", &InvalidNameError{Name: namespace, Path: path}
+	}
+
+	fullname := qualify(namespace, name)
+	if _, err = w.declare(t, fullname, path); err != nil {
+		return "", "", "", err
+	}
+	w.defined[t] = fullname
+	return name, namespace, "", nil
+}
+
+/*
+The Avro name of t, by the Namer if it has one for t or else DefaultName, then overridden by NameMapping.
+*/
+func (w *walker[T]) typeName(t T, path string) string {
+	var name string
+	if rt, ok := w.native(t); ok && w.Namer != nil {
+		name = w.Namer(rt, path)
+	}
+	if name == "" {
+		name = defaultName(w.types.Name(t), path)
+	}
+	return w.mapName(name)
+}
+
+/*
+Override name by NameMapping, if it has an entry for it.
+*/
+func (r *Reflector) mapName(name string) string {
+	if mappedName, ok := r.NameMapping[name]; ok {
+		return mappedName
+	}
+	return name
+}
+
+/*
+DefaultName is the naming policy of a Reflector without a Namer.
+Named types keep their Go name, with the type arguments of generic types appended
+without their package, e.g. Page[github.com/acme/x.Order] becomes Page_Order.
+Anonymous structs are named after the field path they are first met at,
+e.g. the struct of the field `meta` in Order becomes Order_meta.
+*/
+func DefaultName(t reflect.Type, path string) string {
+	return defaultName(t.Name(), path)
+}
+
+/*
+DefaultName given the Go name of a type, empty for anonymous ones.
+*/
+func defaultName(name, path string) string {
+	if name == "" {
+		name = strings.NewReplacer(".", "_", "[]", "", "{}", "").Replace(path)
+		if name == "" {
+			return "Record"
+		}
+		return sanitizeName(name)
+	}
+
+	base, args, generic := strings.Cut(name, "[")
+	if !generic {
+		return name
+	}
+
+	parts := []string{base}
+	for _, arg := range strings.FieldsFunc(args, func(c rune) bool {
+		return strings.ContainsRune("[]*, ", c)
+	}) {
+		// drop the package path, e.g. github.com/acme/x.Order, and the index of function local types
+		arg = arg[strings.LastIndex(arg, "/")+1:]
+		arg = arg[strings.LastIndex(arg, ".")+1:]
+		arg, _, _ = strings.Cut(arg, "·")
+		parts = append(parts, arg)
+	}
+	return sanitizeName(strings.Join(parts, "_"))
+}
+
+func isValidNamespace(namespace string) bool {
+	if namespace == "" {
+		return true
+	}
+	for _, part := range strings.Split(namespace, ".") {
+		if !isValidName(part) {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Declare a named type the reflector makes up, e.g. fixed_16 for an unnamed [16]byte.
+Such types live in the Reflector's Namespace, or else the enclosing one, and are shared
+by every field of the same Go type within it, the type claiming the name.
+*/
+func (w *walker[T]) declareSynthetic(t any, name, path string) (namespace, ref string, err error) {
+	namespace = w.Namespace
+	if namespace == "" {
+		namespace = w.enclosing
+	}
+	if !isValidNamespace(namespace) {
+		return "", "", &InvalidNameError{Name: namespace, Path: path}
+	}
+	fullname := qualify(namespace, name)
+	declared, err := w.declare(t, fullname, path)
+	if err != nil || declared {
+		return "", fullname, err
+	}
+	return namespace, "", nil
+}
+
+/*
+Claim fullname for t, reporting whether t has claimed it before.
+Distinct Go types claiming the same full name would make for an invalid schema.
+The type t is a T of the walk, or a reflect.Type for the synthetic types of durations and decimals.
+*/
+func (w *walker[T]) declare(t any, fullname, path string) (bool, error) {
+	if prev, ok := w.claimed[fullname]; ok {
+		if prev == t {
+			return true, nil
+		}
+		rt, ok := t.(reflect.Type)
+		rPrev, prevOk := prev.(reflect.Type)
+		if !ok || !prevOk {
+			return false, &StaticTypeError{Type: fmt.Sprint(t), Path: path, Reason: fmt.Sprintf("shares the name %s with %s", fullname, prev)}
+		}
+		return false, &NameCollisionError{Name: fullname, Type: rt, Previous: rPrev, Path: path}
+	}
+	w.claimed[fullname] = t
+	return false, nil
+}
+
+func qualify(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// code hosting domains whose second-level label adds nothing to the owner in the path
+var hostingDomains = map[string]bool{
+	"github.com":    true,
+	"gitlab.com":    true,
+	"bitbucket.org": true,
+}
+
+/*
+PackageNamespace derives a namespace from the package path of t, to be used as
+Reflector.Namespacer. The domain is reversed as in Java packages, except for
+code hosting domains which are reduced to their top-level domain:
+
+	github.com/acme/billing -> com.acme.billing
+	go.acme.com/shipping/v2 -> com.acme.go.shipping.v2
+
+Characters which are not valid in Avro names are replaced by underscores.
+*/
+func PackageNamespace(t reflect.Type) string {
+	pkgPath := t.PkgPath()
+	if pkgPath == "" {
+		return ""
+	}
+
+	segments := strings.Split(pkgPath, "/")
+	var parts []string
+	if host := segments[0]; strings.Contains(host, ".") && len(segments) > 1 {
+		labels := strings.Split(host, ".")
+		if hostingDomains[host] {
+			labels = labels[len(labels)-1:]
+		}
+		for i := len(labels) - 1; i >= 0; i-- {
+			parts = append(parts, labels[i])
+		}
+		segments = segments[1:]
+	}
+	parts = append(parts, segments...)
+
+	for i, part := range parts {
+		parts[i] = sanitizeName(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+/*
+Replace the characters which are not valid in Avro names by underscores.
+*/
+func sanitizeName(s string) string {
+	ret := []rune(s)
+	for i, c := range ret {
+		if c > unicode.MaxASCII || !(c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c)) {
+			ret[i] = '_'
+		}
+	}
+	if len(ret) == 0 || unicode.IsDigit(ret[0]) {
+		ret = append([]rune{'_'}, ret...)
+	}
+	return string(ret)
+}
@@ -0,0 +1,488 @@
+package avroschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+/*
+ParseSchema loads Avro schema JSON, e.g. an .avsc file, into an AvroSchema.
+
+Primitive types and references to named types become strings, unions []any and
+everything else *AvroSchema, just like the schemas of a Reflector. A null default
+becomes Null. A top-level primitive or union schema is returned as the Type of an
+otherwise empty AvroSchema. Attributes the AvroSchema has no room for are dropped.
+
+Schemas that violate the Avro specification, e.g. by referring to an undefined name
+or with a default that doesn't match its field, are rejected with a *ParseError.
+*/
+func ParseSchema(data []byte) (*AvroSchema, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, &ParseError{Path: "$", Reason: err.Error()}
+	}
+	if dec.More() {
+		return nil, &ParseError{Path: "$", Reason: "unexpected data after the schema"}
+	}
+
+	p := &schemaParser{names: namedSchemas{}}
+	s, err := p.parse(v, "$", "")
+	if err != nil {
+		return nil, err
+	}
+	if schema, ok := s.(*AvroSchema); ok {
+		return schema, nil
+	}
+	return &AvroSchema{Type: s}, nil
+}
+
+type schemaParser struct {
+	names namedSchemas // named types defined so far
+}
+
+func parseErr(path, format string, args ...any) error {
+	return &ParseError{Path: path, Reason: fmt.Sprintf(format, args...)}
+}
+
+/*
+Return type is either a string, a *AvroSchema or a union, i.e. a []any.
+*/
+func (p *schemaParser) parse(v any, path, namespace string) (any, error) {
+	switch v := v.(type) {
+	case string:
+		if !primitiveTypes[v] {
+			if _, ok := p.names.lookup(v, namespace); !ok {
+				return nil, parseErr(path, "undefined name %q", v)
+			}
+		}
+		return v, nil
+	case []any:
+		return p.parseUnion(v, path, namespace)
+	case map[string]any:
+		return p.parseObject(v, path, namespace)
+	}
+	return nil, parseErr(path, "a schema must be a string, an array or an object")
+}
+
+func (p *schemaParser) parseUnion(v []any, path, namespace string) ([]any, error) {
+	union := make([]any, 0, len(v))
+	seen := map[string]bool{}
+	for i, b := range v {
+		bpath := fmt.Sprintf("%s[%d]", path, i)
+		branch, err := p.parse(b, bpath, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved, ns, _ := p.names.resolve(branch, namespace)
+		key := schemaKind(resolved)
+		switch r := resolved.(type) {
+		case []any:
+			return nil, parseErr(bpath, "unions may not immediately contain other unions")
+		case *AvroSchema:
+			if isNamedType(r.Type) {
+				key, _ = fullName(r.Name, r.Namespace, ns)
+			}
+		}
+		if seen[key] {
+			return nil, parseErr(bpath, "duplicate %s in union", key)
+		}
+		seen[key] = true
+		union = append(union, branch)
+	}
+	return union, nil
+}
+
+func (p *schemaParser) parseObject(m map[string]any, path, namespace string) (any, error) {
+	typ, ok := m["type"]
+	if !ok {
+		return nil, parseErr(path, "missing type")
+	}
+	t, ok := typ.(string)
+	if !ok {
+		return nil, parseErr(path+".type", "the type of a schema object must be a string")
+	}
+
+	switch t {
+	case "record", "error":
+		return p.parseRecord(m, t, path, namespace)
+	case "enum":
+		return p.parseEnum(m, path, namespace)
+	case "fixed":
+		s, _, err := p.parseNamed(m, t, path, namespace)
+		if err != nil {
+			return nil, err
+		}
+		size, ok, err := intAttr(m, "size", path)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, parseErr(path, "missing size")
+		}
+		s.Size = size
+		return s, p.parseLogical(s, m, path)
+	case "array":
+		items, ok := m["items"]
+		if !ok {
+			return nil, parseErr(path, "missing items")
+		}
+		s := &AvroSchema{Type: t}
+		var err error
+		if s.Items, err = p.parse(items, path+".items", namespace); err != nil {
+			return nil, err
+		}
+		return s, nil
+	case "map":
+		values, ok := m["values"]
+		if !ok {
+			return nil, parseErr(path, "missing values")
+		}
+		s := &AvroSchema{Type: t}
+		var err error
+		if s.Values, err = p.parse(values, path+".values", namespace); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	if !primitiveTypes[t] {
+		// a wrapped reference, e.g. {"type": "com.example.Node"}
+		return p.parse(t, path+".type", namespace)
+	}
+	s := &AvroSchema{Type: t}
+	return s, p.parseLogical(s, m, path)
+}
+
+/*
+Parse and define the name of a named type, before its fields so that they may refer back to it.
+Return the namespace that names within it are relative to.
+*/
+func (p *schemaParser) parseNamed(m map[string]any, typ, path, enclosing string) (*AvroSchema, string, error) {
+	name, ok, err := stringAttr(m, "name", path)
+	if err != nil {
+		return nil, "", err
+	}
+	if !ok {
+		return nil, "", parseErr(path, "missing name")
+	}
+	namespace, _, err := stringAttr(m, "namespace", path)
+	if err != nil {
+		return nil, "", err
+	}
+	if !isValidNamespace(namespace) {
+		return nil, "", parseErr(path+".namespace", "invalid namespace %q", namespace)
+	}
+
+	full, ns := fullName(name, namespace, enclosing)
+	if !isValidName(lastName(full)) || !isValidNamespace(full) {
+		return nil, "", parseErr(path+".name", "invalid name %q", name)
+	}
+	if primitiveTypes[lastName(full)] {
+		return nil, "", parseErr(path+".name", "%q is a primitive type and cannot be redefined", name)
+	}
+	if _, ok := p.names[full]; ok {
+		return nil, "", parseErr(path+".name", "%q is already defined", full)
+	}
+
+	s := &AvroSchema{Name: name, Type: typ, Namespace: namespace}
+	if s.Doc, _, err = stringAttr(m, "doc", path); err != nil {
+		return nil, "", err
+	}
+	if s.Aliases, err = aliasesAttr(m, path); err != nil {
+		return nil, "", err
+	}
+	p.names[full] = namedSchema{schema: s, namespace: ns}
+	return s, ns, nil
+}
+
+func lastName(fullname string) string {
+	return fullname[strings.LastIndexByte(fullname, '.')+1:]
+}
+
+func (p *schemaParser) parseRecord(m map[string]any, typ, path, enclosing string) (*AvroSchema, error) {
+	s, namespace, err := p.parseNamed(m, typ, path, enclosing)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, ok := m["fields"].([]any)
+	if !ok {
+		return nil, parseErr(path, "missing fields")
+	}
+	s.Fields = []*AvroSchema{}
+	seen := map[string]bool{}
+	for i, f := range fields {
+		fpath := fmt.Sprintf("%s.fields[%d]", path, i)
+		fm, ok := f.(map[string]any)
+		if !ok {
+			return nil, parseErr(fpath, "a field must be an object")
+		}
+		field, err := p.parseField(fm, fpath, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if seen[field.Name] {
+			return nil, parseErr(fpath+".name", "duplicate field %q", field.Name)
+		}
+		seen[field.Name] = true
+		s.Fields = append(s.Fields, field)
+	}
+	return s, nil
+}
+
+func (p *schemaParser) parseField(m map[string]any, path, namespace string) (*AvroSchema, error) {
+	name, ok, err := stringAttr(m, "name", path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, parseErr(path, "missing name")
+	}
+	if !isValidName(name) {
+		return nil, parseErr(path+".name", "invalid name %q", name)
+	}
+	typ, ok := m["type"]
+	if !ok {
+		return nil, parseErr(path, "missing type")
+	}
+
+	field := &AvroSchema{Name: name}
+	if field.Type, err = p.parse(typ, path+".type", namespace); err != nil {
+		return nil, err
+	}
+	if field.Doc, _, err = stringAttr(m, "doc", path); err != nil {
+		return nil, err
+	}
+	if field.Aliases, err = aliasesAttr(m, path); err != nil {
+		return nil, err
+	}
+	if field.Order, _, err = stringAttr(m, "order", path); err != nil {
+		return nil, err
+	}
+	switch field.Order {
+	case "", "ascending", "descending", "ignore":
+	default:
+		return nil, parseErr(path+".order", "invalid order %q", field.Order)
+	}
+	// logical types flattened into the field, as the Reflector emits them
+	if err = p.parseLogical(field, m, path); err != nil {
+		return nil, err
+	}
+
+	if def, ok := m["default"]; ok {
+		if !p.validDefault(fieldType(field), def, namespace) {
+			return nil, parseErr(path+".default", "default %s does not match the field type", mustJSON(def))
+		}
+		field.Default = def
+		if def == nil {
+			field.Default = Null{}
+		}
+	}
+	return field, nil
+}
+
+func (p *schemaParser) parseEnum(m map[string]any, path, enclosing string) (*AvroSchema, error) {
+	s, _, err := p.parseNamed(m, "enum", path, enclosing)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols, ok := m["symbols"].([]any)
+	if !ok {
+		return nil, parseErr(path, "missing symbols")
+	}
+	for i, sym := range symbols {
+		name, ok := sym.(string)
+		spath := fmt.Sprintf("%s.symbols[%d]", path, i)
+		if !ok || !isValidName(name) {
+			return nil, parseErr(spath, "invalid symbol %s", mustJSON(sym))
+		}
+		if slices.Contains(s.Symbols, name) {
+			return nil, parseErr(spath, "duplicate symbol %q", name)
+		}
+		s.Symbols = append(s.Symbols, name)
+	}
+
+	def, ok, err := stringAttr(m, "default", path)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		if !slices.Contains(s.Symbols, def) {
+			return nil, parseErr(path+".default", "default %q is not a symbol", def)
+		}
+		s.Default = def
+	}
+	return s, nil
+}
+
+func (p *schemaParser) parseLogical(s *AvroSchema, m map[string]any, path string) error {
+	var err error
+	if s.LogicalType, _, err = stringAttr(m, "logicalType", path); err != nil {
+		return err
+	}
+	if s.Precision, _, err = intAttr(m, "precision", path); err != nil {
+		return err
+	}
+	if s.Scale, _, err = intAttr(m, "scale", path); err != nil {
+		return err
+	}
+	if s.LogicalType == "decimal" && s.Scale > s.Precision {
+		return parseErr(path+".scale", "scale %d exceeds precision %d", s.Scale, s.Precision)
+	}
+	return nil
+}
+
+/*
+Check a default value against its schema, a union's default matches its first branch.
+*/
+func (p *schemaParser) validDefault(s any, v any, namespace string) bool {
+	s, namespace, ok := p.names.resolve(s, namespace)
+	if !ok {
+		return false
+	}
+
+	var typ any = s
+	schema, isSchema := s.(*AvroSchema)
+	if isSchema {
+		typ = schema.Type
+	}
+	switch typ := typ.(type) {
+	case []any:
+		return len(typ) > 0 && p.validDefault(typ[0], v, namespace)
+	case string:
+		if primitiveTypes[typ] {
+			return validPrimitiveDefault(typ, v)
+		}
+		return isSchema && p.validComplexDefault(schema, v, namespace)
+	}
+	return false
+}
+
+func validPrimitiveDefault(typ string, v any) bool {
+	switch typ {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "int", "long":
+		n, ok := v.(float64)
+		return ok && n == math.Trunc(n)
+	case "float", "double":
+		_, ok := v.(float64)
+		return ok
+	case "bytes", "string":
+		_, ok := v.(string)
+		return ok
+	}
+	return false
+}
+
+/*
+Check the default of a named type, an array or a map, whose items, values and fields are checked in turn.
+*/
+func (p *schemaParser) validComplexDefault(s *AvroSchema, v any, namespace string) bool {
+	switch s.Type {
+	case "fixed":
+		_, ok := v.(string)
+		return ok
+	case "enum":
+		sym, ok := v.(string)
+		return ok && slices.Contains(s.Symbols, sym)
+	case "array":
+		items, ok := v.([]any)
+		for _, item := range items {
+			if !p.validDefault(s.Items, item, namespace) {
+				return false
+			}
+		}
+		return ok
+	case "map":
+		values, ok := v.(map[string]any)
+		for _, value := range values {
+			if !p.validDefault(s.Values, value, namespace) {
+				return false
+			}
+		}
+		return ok
+	case "record", "error":
+		return p.validRecordDefault(s, v, namespace)
+	}
+	return false
+}
+
+func (p *schemaParser) validRecordDefault(s *AvroSchema, v any, namespace string) bool {
+	record, ok := v.(map[string]any)
+	if !ok {
+		return false
+	}
+	for _, f := range s.Fields {
+		value, ok := record[f.Name]
+		if !ok {
+			if f.Default == nil {
+				return false
+			}
+			continue
+		}
+		if !p.validDefault(fieldType(f), value, namespace) {
+			return false
+		}
+	}
+	return true
+}
+
+func stringAttr(m map[string]any, key, path string) (string, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return "", false, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", false, parseErr(path+"."+key, "%s must be a string", key)
+	}
+	return s, true, nil
+}
+
+func intAttr(m map[string]any, key, path string) (int, bool, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, false, nil
+	}
+	n, ok := v.(float64)
+	if !ok || n != math.Trunc(n) || n < 0 {
+		return 0, false, parseErr(path+"."+key, "%s must be a non-negative integer", key)
+	}
+	return int(n), true, nil
+}
+
+func aliasesAttr(m map[string]any, path string) ([]string, error) {
+	v, ok := m["aliases"]
+	if !ok {
+		return nil, nil
+	}
+	aliases, ok := v.([]any)
+	if !ok {
+		return nil, parseErr(path+".aliases", "aliases must be an array of names")
+	}
+	var ret []string
+	for i, a := range aliases {
+		alias, ok := a.(string)
+		if !ok || !isValidNamespace(alias) {
+			return nil, parseErr(fmt.Sprintf("%s.aliases[%d]", path, i), "invalid alias %s", mustJSON(a))
+		}
+		ret = append(ret, alias)
+	}
+	return ret, nil
+}
+
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
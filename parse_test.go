@@ -0,0 +1,119 @@
+package avroschema
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSchema(t *testing.T) {
+	avsc := `{
+    "name": "Order",
+    "type": "record",
+    "namespace": "com.example",
+    "doc": "A customer order",
+    "fields": [
+      {"name": "id", "type": {"type": "string", "logicalType": "uuid"}},
+      {"name": "status", "type": {"name": "Status", "type": "enum", "symbols": ["OPEN", "PAID"], "default": "OPEN"}, "default": "OPEN"},
+      {"name": "hash", "type": {"name": "md5", "type": "fixed", "size": 16}},
+      {"name": "amount", "type": {"type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2}},
+      {"name": "placed", "type": "long", "logicalType": "timestamp-millis"},
+      {"name": "items", "type": {"type": "array", "items": {"name": "Item", "type": "record", "fields": [
+        {"name": "sku", "type": "string", "aliases": ["code"]},
+        {"name": "quantity", "type": "int", "default": 1, "order": "descending"}
+      ]}}},
+      {"name": "gift", "type": ["null", "Item"], "default": null},
+      {"name": "labels", "type": {"type": "map", "values": "string"}, "default": {}},
+      {"name": "parent", "type": ["null", "com.example.Order"], "default": null, "doc": "reordered from"}
+    ]
+  }`
+
+	s, err := ParseSchema([]byte(avsc))
+	assert.Nil(t, err)
+	assert.Equal(t, "Order", s.Name)
+	assert.Equal(t, []any{"null", "Item"}, s.Fields[6].Type)
+	assert.Equal(t, Null{}, s.Fields[6].Default)
+	assert.Equal(t, &AvroSchema{Type: "string", LogicalType: "uuid"}, s.Fields[0].Type)
+	assert.Equal(t, 16, s.Fields[2].Type.(*AvroSchema).Size)
+
+	r, err := StructToJson(s)
+	assert.Nil(t, err)
+	assert.JSONEq(t, avsc, r)
+
+	for _, primitive := range []string{`"string"`, `["null", "long"]`} {
+		s, err := ParseSchema([]byte(primitive))
+		assert.Nil(t, err)
+		r, err := StructToJson(s.Type)
+		assert.Nil(t, err)
+		assert.JSONEq(t, primitive, r)
+	}
+}
+
+func TestParseReflectedSchema(t *testing.T) {
+	type Line struct {
+		SKU    string   `json:"sku"`
+		Amount *big.Rat `json:"amount" avro:",precision=10,scale=2"`
+	}
+	type Invoice struct {
+		ID      UUID              `json:"id"`
+		Suit    Suit              `json:"suit"`
+		Lines   []Line            `json:"lines"`
+		Labels  map[string]string `json:"labels"`
+		Issued  time.Time         `json:"issued"`
+		Note    string            `json:"note,omitempty"`
+		Count   int               `json:"count" avro:",default=3,doc=how many"`
+		Manager Employee          `json:"manager"`
+		Payment PaymentMethod     `json:"payment"`
+	}
+
+	reflector := &Reflector{Namespace: "com.example", Unions: map[reflect.Type]UnionSpec{
+		paymentMethodType: {Members: []reflect.Type{reflect.TypeOf(Card{}), reflect.TypeOf(BankTransfer{})}, Nullable: true},
+	}}
+	expected, err := reflector.ReflectSchema(&Invoice{})
+	assert.Nil(t, err)
+
+	r, err := StructToJson(expected)
+	assert.Nil(t, err)
+	s, err := ParseSchema([]byte(r))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, s)
+}
+
+func TestParseSchemaError(t *testing.T) {
+	tests := []struct {
+		avsc string
+		path string
+	}{
+		{`{"name": "A", "type": "record", "fields": [`, "$"},
+		{`{"name": "A"}`, "$"},
+		{`{"name": "A", "type": "record"}`, "$"},
+		{`{"name": "A-B", "type": "record", "fields": []}`, "$.name"},
+		{`{"name": "int", "type": "fixed", "size": 4}`, "$.name"},
+		{`{"name": "A", "type": "fixed", "size": -1}`, "$.size"},
+		{`{"name": "A", "type": "record", "fields": [{"name": "a", "type": "Missing"}]}`, "$.fields[0].type"},
+		{`{"name": "A", "type": "record", "fields": [{"name": "a", "type": "int"}, {"name": "b", "type": ["null", "string", "string"]}]}`, "$.fields[1].type[2]"},
+		{`{"name": "A", "type": "record", "fields": [{"name": "a", "type": ["null", ["int"]]}]}`, "$.fields[0].type[1]"},
+		{`{"name": "A", "type": "record", "fields": [{"name": "a", "type": "int"}, {"name": "a", "type": "int"}]}`, "$.fields[1].name"},
+		{`{"name": "A", "type": "record", "fields": [{"name": "a", "type": "int", "default": "x"}]}`, "$.fields[0].default"},
+		{`{"name": "A", "type": "record", "fields": [{"name": "a", "type": ["null", "int"], "default": 1}]}`, "$.fields[0].default"},
+		{`{"name": "A", "type": "record", "fields": [{"name": "a", "type": "int", "order": "up"}]}`, "$.fields[0].order"},
+		{`{"name": "A", "type": "record", "fields": [{"name": "a", "type": {"type": "array"}}]}`, "$.fields[0].type"},
+		{`{"name": "A", "type": "record", "fields": [{"name": "a", "type": {"type": "map", "values": "B"}}]}`, "$.fields[0].type.values"},
+		{`{"name": "A", "type": "record", "fields": [{"name": "a", "type": {"name": "A", "type": "fixed", "size": 1}}]}`, "$.fields[0].type.name"},
+		{`{"name": "E", "type": "enum", "symbols": ["A", "A"]}`, "$.symbols[1]"},
+		{`{"name": "E", "type": "enum", "symbols": ["A"], "default": "B"}`, "$.default"},
+		{`{"type": "bytes", "logicalType": "decimal", "precision": 2, "scale": 4}`, "$.scale"},
+	}
+
+	for _, test := range tests {
+		_, err := ParseSchema([]byte(test.avsc))
+		var parseErr *ParseError
+		if assert.True(t, errors.As(err, &parseErr), test.avsc) {
+			assert.Equal(t, test.path, parseErr.Path, test.avsc)
+		}
+	}
+}
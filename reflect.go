@@ -1,138 +1,277 @@
 package avroschema
 
 import (
+	"fmt"
 	"reflect"
-	"strings"
-	"time"
+	"sync"
 )
 
-var timeType = reflect.TypeOf(time.Time{})
-
+/*
+A Reflector is safe for concurrent use and caches the schema of every root type it reflects,
+as long as its options are not changed in the meantime. Changing them between calls is fine
+and reflects the type anew. A Mapper, Namer or Namespacer must not call back into the same Reflector.
+*/
 type Reflector struct {
 	/*
 	   Make all fields of Record be backward transitive, i.e., all fields are optional.
 	*/
 	BeBackwardTransitive bool
 	EmitAllFields        bool // don't skip struct fields which have no struct tags
-	SkipTagFieldNames    bool // don't use json/bson tag names, even if theyre present
+	SkipTagFieldNames    bool // don't use avro/json/bson tag names, even if theyre present
 	Mapper               func(reflect.Type) any
-	NameMapping          map[string]string // override record's name
+	NameMapping          map[string]string          // override record's name
+	Enums                map[reflect.Type]EnumSpec  // enum types which don't implement AvroEnum
+	Unions               map[reflect.Type]UnionSpec // implementations of interface types
+	MapKeys              MapKeyMode                 // how to emit maps with non-string keys
+	NullablePointers     bool                       // make pointer, slice and map fields nullable with a null default
 	Namespace            string
-	recordTypeCache      map[string]reflect.Type
+	Strict               bool                                     // return an error instead of falling back to "string" for unmappable types
+	DecimalPrecision     int                                      // precision of decimal fields without a precision tag option
+	DecimalScale         int                                      // scale of decimal fields without a scale tag option
+	BigDecimal           bool                                     // emit big-decimal for decimal fields without a precision tag option
+	TimePrecision        TimePrecision                            // precision of time.Time and TimeOfDay fields, milliseconds by default
+	LocalTimestamps      bool                                     // emit local-timestamp-* instead of timestamp-* for time.Time fields
+	DurationAsFixed      bool                                     // emit time.Duration with the duration logical type instead of long
+	UUIDAsFixed          bool                                     // emit [16]byte UUIDs as fixed(16) instead of string
+	Namespacer           func(reflect.Type) string                // namespace of named types, e.g. PackageNamespace, overriding Namespace
+	Namer                func(t reflect.Type, path string) string // name of named types, DefaultName when nil or empty
+	Docs                 DocRegistry                              // docs of types and fields without a doc tag option, e.g. read by static.ParseDocs
+	mu                   sync.RWMutex                             // guards the cache
+	cache                map[reflect.Type]*cachedSchema           // finished schemas of root types
 }
 
 /*
-Return type is either a string, a *AvroSchema of a slice of *AvroSchema.
+Return type is either a string, a *AvroSchema, a slice of *AvroSchema or a union, i.e. a []any.
+The path is the field path of t and is only used for error reporting,
+the tag holds the options of the field t belongs to.
 */
-func (r *Reflector) reflectType(t reflect.Type) any {
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
+func (w *walker[T]) reflectType(t T, path string, tag *avroTag) (any, error) {
+	if w.types.Kind(t) == reflect.Ptr {
+		t = w.types.Elem(t)
 	}
 
-	if r.Mapper != nil {
-		if ret := r.Mapper(t); ret != nil {
-			return ret
+	if rt, ok := w.native(t); ok && w.Mapper != nil {
+		if ret := w.Mapper(rt); ret != nil {
+			return w.checkMapperResult(rt, path, ret)
 		}
 	}
 
-	switch t.Kind() {
+	if ret, ok, err := w.handleSpecial(t, tag, path); ok {
+		return ret, err
+	}
+
+	switch w.types.Kind(t) {
 	case reflect.String:
-		return "string"
+		return "string", nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
-		return "int"
+		return "int", nil
 	case reflect.Int64, reflect.Uint64:
-		return "long"
+		return "long", nil
 	case reflect.Float32:
-		return "float"
+		return "float", nil
 	case reflect.Float64:
-		return "double"
+		return "double", nil
 	case reflect.Bool:
-		return "boolean"
-	case reflect.Array, reflect.Slice:
-		return r.handleArray(t)
-	case reflect.Struct:
-		// handle special built-in types, e.g. time.Time
-		if t == timeType {
-			return &AvroSchema{Type: "long", LogicalType: "timestamp-millis"}
+		return "boolean", nil
+	case reflect.Slice, reflect.Array:
+		if w.types.Kind(w.types.Elem(t)) == reflect.Uint8 {
+			return w.handleBytes(t, path)
 		}
-		rec := r.handleRecord(t)
-		// cache record result for future references
-		r.recordTypeCache[t.Name()] = t
-		return rec
+		return w.handleArray(t, path, tag)
+	case reflect.Struct:
+		return w.handleRecord(t, path)
 	case reflect.Map:
-		if t.Key().Kind() != reflect.String {
-			// If the key is not a string, then treat the whole object as a string.
-			return "string"
+		if w.types.Kind(w.types.Key(t)) != reflect.String {
+			return w.handleNonStringMap(t, path, tag)
+		}
+		return w.handleMap(t, path, tag)
+	case reflect.Interface:
+		if rt, ok := w.native(t); ok {
+			if spec, ok := w.Unions[rt]; ok {
+				return w.handleUnion(rt, spec, path)
+			}
 		}
-		return r.handleMap(t)
+		return w.fallback(t, path)
 	default:
-		return "string" // any
+		// chan, func, complex and unsafe.Pointer kinds
+		return w.fallback(t, path)
 	}
 }
 
-func (r *Reflector) handleMap(t reflect.Type) *AvroSchema {
-	return &AvroSchema{Type: "map", Values: r.reflectType(t.Elem())}
+/*
+Map the types whose schema depends on more than their kind, i.e. temporal types, UUIDs,
+decimals and enums, reporting whether t is one of them.
+*/
+func (w *walker[T]) handleSpecial(t T, tag *avroTag, path string) (any, bool, error) {
+	rt, known := w.types.Runtime(t)
+	if known {
+		if ret, ok, err := w.handleTemporal(rt, tag, path); ok {
+			return ret, true, err
+		}
+	}
+
+	// tag options of arrays and maps apply to their items and values
+	if !w.isContainer(t) {
+		if tag.LogicalType != "" {
+			ret, err := logicalInteger(w.types.String(t), w.types.Kind(t), tag, path)
+			return ret, true, err
+		}
+
+		if w.isUUID(t, tag) {
+			ret, err := w.handleUUID(t, tag, path)
+			return ret, true, err
+		}
+
+		if bigNumber := known && (rt == bigRatType || rt == bigFloatType); bigNumber || tag.Decimal {
+			ret, err := w.handleDecimal(t, bigNumber, tag, path)
+			return ret, true, err
+		}
+	}
+
+	spec, err := w.enumSpec(t, path)
+	if err != nil {
+		return nil, true, err
+	}
+	if spec != nil {
+		ret, err := w.handleEnum(t, spec, path)
+		return ret, true, err
+	}
+	return nil, false, nil
 }
 
-func (r *Reflector) handleArray(t reflect.Type) *AvroSchema {
-	return &AvroSchema{Type: "array", Items: r.reflectType(t.Elem())}
+/*
+Types without an Avro counterpart are emitted as "string", unless the Reflector is strict.
+*/
+func (w *walker[T]) fallback(t T, path string) (any, error) {
+	if !w.Strict {
+		return "string", nil
+	}
+	if rt, ok := w.native(t); ok {
+		return nil, &UnsupportedTypeError{Type: rt, Path: path}
+	}
+	return nil, w.typeError(t, path, "unsupported type")
 }
 
-func (r *Reflector) handleRecord(t reflect.Type) *AvroSchema {
-	name := t.Name()
-	tokens := strings.Split(name, ".")
-	name = tokens[len(tokens)-1]
+func (r *Reflector) checkMapperResult(t reflect.Type, path string, ret any) (any, error) {
+	if !r.Strict {
+		return ret, nil
+	}
+	switch ret.(type) {
+	case string, *AvroSchema, []*AvroSchema, []any:
+		return ret, nil
+	}
+	return nil, &InvalidMapperResultError{Type: t, Path: path, Result: ret}
+}
 
-	if r.NameMapping != nil {
-		if mappedName, ok := r.NameMapping[name]; ok {
-			name = mappedName
-		}
+func (w *walker[T]) handleMap(t T, path string, tag *avroTag) (*AvroSchema, error) {
+	values, err := w.reflectType(w.types.Elem(t), path+"{}", tag)
+	if err != nil {
+		return nil, err
+	}
+	return &AvroSchema{Type: "map", Values: values}, nil
+}
+
+func (w *walker[T]) handleArray(t T, path string, tag *avroTag) (*AvroSchema, error) {
+	items, err := w.reflectType(w.types.Elem(t), path+"[]", tag)
+	if err != nil {
+		return nil, err
 	}
+	return &AvroSchema{Type: "array", Items: items}, nil
+}
 
-	if _, ok := r.recordTypeCache[t.Name()]; ok {
-		return &AvroSchema{Name: name, Type: t.Name()}
+/*
+Byte slices are bytes, byte arrays fixed types.
+*/
+func (w *walker[T]) handleBytes(t T, path string) (any, error) {
+	if w.types.Kind(t) == reflect.Slice {
+		return "bytes", nil
 	}
+	return w.handleFixed(t, path)
+}
 
-	ret := &AvroSchema{
+/*
+Fixed-size byte arrays become named fixed types, unnamed ones are named after their size.
+Return type is either the fixed *AvroSchema or, if already defined, its full name.
+*/
+func (w *walker[T]) handleFixed(t T, path string) (any, error) {
+	size := w.types.Len(t)
+	var name, namespace, ref string
+	var err error
+	if w.types.Name(t) == "" {
+		name = fmt.Sprintf("fixed_%d", size)
+		namespace, ref, err = w.declareSynthetic(t, name, path)
+	} else {
+		name, namespace, ref, err = w.declareType(t, path)
+	}
+	if err != nil || ref != "" {
+		return ref, err
+	}
+
+	return &AvroSchema{
 		Name:      name,
-		Type:      "record",
-		Namespace: r.Namespace,
+		Type:      "fixed",
+		Namespace: namespace,
+		Doc:       w.typeDoc(t),
+		Size:      size,
+	}, nil
+}
+
+/*
+Return type is either the record *AvroSchema or, if already defined, its full name.
+*/
+func (w *walker[T]) handleRecord(t T, path string) (any, error) {
+	// declare the record before walking its fields, so that recursive types refer back to it
+	name, namespace, ref, err := w.declareType(t, path)
+	if err != nil || ref != "" {
+		return ref, err
 	}
 
-	for i, n := 0, t.NumField(); i < n; i++ { // handle fields
-		f := t.Field(i)
+	enclosing := w.enclosing
+	w.enclosing = namespace
+	fields, err := w.recordFields(t, path)
+	w.enclosing = enclosing
+	if err != nil {
+		return nil, err
+	}
 
-		jsonTag := f.Tag.Get("json")
-		jStructTag := parseStructTag(jsonTag)
-		bsonTag := f.Tag.Get("bson")
-		bStructTag := parseStructTag(bsonTag)
-		// for inline structs go and pull the fields and append to this record
-		if jStructTag.Inline || bStructTag.Inline {
-			ret.Fields = append(ret.Fields, r.handleRecord(f.Type).Fields...)
-			continue
-		}
+	return &AvroSchema{
+		Name:      name,
+		Type:      "record",
+		Namespace: namespace,
+		Doc:       w.typeDoc(t),
+		Fields:    fields,
+	}, nil
+}
 
-		// unless emitting all fields, ignore fields with no json/bson tag names
-		if !r.EmitAllFields && jStructTag.Name == "" && bStructTag.Name == "" {
-			continue
+func (w *walker[T]) recordFields(t T, path string) ([]*AvroSchema, error) {
+	fields, err := w.collectFields(t, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*AvroSchema
+	for _, f := range fields {
+		if !isValidName(f.tag.Name) {
+			return nil, &InvalidNameError{Name: f.tag.Name, Path: path + "." + f.tag.Name}
 		}
-		fieldName := f.Name
-		if !r.SkipTagFieldNames {
-			// prefer bson tag name in attempt at more compatability with this MgmExtension thing, the mapper for which mimics the bson naming
-			if bStructTag.Name != "" {
-				fieldName = bStructTag.Name
-			} else if jStructTag.Name != "" {
-				fieldName = jStructTag.Name
-			}
-			// otherwise must be emitting all fields and so no other choice than to take the go name
+		if f.tag.Doc == "" {
+			f.tag.Doc = w.Docs.fieldDoc(w.types.PkgPath(f.owner), w.types.Name(f.owner), f.goName)
 		}
-		// This is likely a backwards compatilbity break with whatever the mgm stuff is, as ObjectID is marked optional in bson, not in json.
-		// previously bson's optional was never considered here.
-		isOptional := jStructTag.Optional || bStructTag.Optional
-		ret.Fields = append(ret.Fields, r.reflectEx(f.Type, isOptional, fieldName)...)
+		schemas, err := w.reflectEx(f.typ, f.tag, path+"."+f.tag.Name)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, schemas...)
 	}
-	return ret
+	return ret, nil
+}
+
+/*
+The doc of the named type t, as registered in the Reflector's Docs.
+*/
+func (w *walker[T]) typeDoc(t T) string {
+	return w.Docs.typeDoc(w.types.PkgPath(t), w.types.Name(t))
 }
 
 /*
@@ -140,52 +279,151 @@ Fill in the Name for the AvroSchema.
 If the reflectType is a simple string, generate an AvroSchema and filled in Type.
 But if it is already an AvroSchema, only the Name needs to be filled in.
 */
-func (r *Reflector) reflectEx(t reflect.Type, isOpt bool, n string) []*AvroSchema {
-	ret := r.reflectType(t)
+func (w *walker[T]) reflectEx(t T, tag *avroTag, path string) ([]*AvroSchema, error) {
+	if w.NullablePointers && isNilable(w.types.Kind(t)) {
+		tag.Optional = true
+	}
+	// without a null default, adding an optional field would not be backward compatible
+	if (tag.Optional || w.BeBackwardTransitive) && tag.Default == nil {
+		tag.Default = Null{}
+	}
+
+	var ret any = "string"
+	if !tag.Quoted {
+		var err error
+		if ret, err = w.reflectType(t, path, tag); err != nil {
+			return nil, err
+		}
+	}
+
+	field, ok := w.fieldSchema(ret, tag)
+	if !ok {
+		// made by extension, i.e., a slice
+		if slice, ok := ret.([]*AvroSchema); ok {
+			return slice, nil
+		}
+		// unexpected mapper results are rejected by checkMapperResult in strict mode
+		return nil, nil
+	}
+	field.Default = tag.Default
+	field.Doc = tag.Doc
+	field.Aliases = tag.Aliases
+	field.Order = tag.Order
+	if tag.HasDefault {
+		w.tagDefaults = append(w.tagDefaults, tagDefault{field: field, tag: tag, path: path})
+	}
+	return []*AvroSchema{field}, nil
+}
+
+/*
+A default given by the tag of a field, checked against the field's schema once the walk is done,
+when every named type it may refer to is defined.
+*/
+type tagDefault struct {
+	field *AvroSchema
+	tag   *avroTag
+	path  string
+}
+
+/*
+Check the tag defaults of the fields of root against their schemas, as ParseSchema would.
+A null default only matches optional fields.
+*/
+func (w *walker[T]) checkDefaults(root *AvroSchema) error {
+	p := &schemaParser{names: indexSchema(root)}
+	for _, d := range w.tagDefaults {
+		v := d.field.Default
+		if v == (Null{}) {
+			v = nil
+		}
+		if !p.validDefault(fieldType(d.field), v, "") {
+			return &InvalidTagError{Tag: d.tag.Raw, Path: d.path, Reason: fmt.Sprintf("default %s does not match the field type", mustJSON(v))}
+		}
+	}
+	return nil
+}
+
+func (r *Reflector) fieldSchema(ret any, tag *avroTag) (*AvroSchema, bool) {
+	n := tag.Name
 
 	// optional field
-	if isOpt || r.BeBackwardTransitive {
-		return []*AvroSchema{{Name: n, Type: []any{"null", ret}}}
+	if tag.Optional || r.BeBackwardTransitive {
+		// a union's default must match its first branch
+		return &AvroSchema{Name: n, Type: nullable(ret, tag.Default == Null{})}, true
+	}
+
+	if union, ok := ret.([]any); ok {
+		return &AvroSchema{Name: n, Type: union}, true
 	}
 
 	// primitive type
 	if reflect.TypeOf(ret).Kind() == reflect.String {
-		return []*AvroSchema{{Name: n, Type: ret}}
+		return &AvroSchema{Name: n, Type: ret}, true
 	}
 
 	result, ok := ret.(*AvroSchema)
-	// made by extension, i.e., a slice
 	if !ok {
-		if slice, ok := ret.([]*AvroSchema); ok {
-			return slice
-		}
-		return nil // FIXME: no error handle
+		return nil, false
 	}
 
 	// If its one of these complex types then name this separately and embed the type as its own schema
-	// unions are already handled explicitly above, fixed and enums not yet supported.
-	if !isOpt && (result.Type == "record" || result.Type == "map" || result.Type == "array") {
-		return []*AvroSchema{{Name: n, Type: ret}}
+	// unions are already handled explicitly above.
+	switch result.Type {
+	case "record", "map", "array", "fixed", "enum":
+		return &AvroSchema{Name: n, Type: ret}, true
+	}
+
+	// parsers ignore logical types given as field attributes, so they are nested as well,
+	// except for timestamp-millis which time.Time fields have always been emitted with
+	if result.LogicalType != "" && result.LogicalType != "timestamp-millis" {
+		return &AvroSchema{Name: n, Type: ret}, true
 	}
 
 	// the rest is single schema
 	result.Name = n
-	return []*AvroSchema{result}
+	return result, true
+}
+
+/*
+Reflect the struct type of v into a record schema, for further processing before serializing it.
+The schema is a copy of the cached one, so it may be modified freely.
+*/
+func (r *Reflector) ReflectSchema(v any) (*AvroSchema, error) {
+	entry, err := r.reflectCached(rootType(v))
+	if err != nil {
+		return nil, err
+	}
+	return cloneSchema(entry.schema).(*AvroSchema), nil
 }
 
 func (r *Reflector) ReflectFromType(v any) (string, error) {
-	// currently everything flows through here so (re)init record cache
-	r.recordTypeCache = make(map[string]reflect.Type)
+	entry, err := r.reflectCached(rootType(v))
+	if err != nil {
+		return "", err
+	}
+	return entry.json, nil
+}
 
+/*
+The type of v, or of what it points to, nil for a nil v.
+*/
+func rootType(v any) reflect.Type {
 	t := reflect.TypeOf(v)
 
-	if t.Kind() == reflect.Ptr {
+	if t != nil && t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
+	return t
+}
 
-	data := r.handleRecord(t)
-
-	return StructToJson(data)
+/*
+Walk the root type t, the caller holds the write lock.
+*/
+func (r *Reflector) reflectRoot(t reflect.Type) (*AvroSchema, error) {
+	if t == nil {
+		return nil, &InvalidRootError{}
+	}
+	return ReflectWith[reflect.Type](r, runtimeTypes{}, t)
 }
 
 /*
@@ -195,8 +433,13 @@ func (r *Reflector) Reflect(v any) (string, error) {
 	return r.ReflectFromType(v)
 }
 
+// shared by the package level functions, so that their schemas are cached as well
+var defaultReflector = &Reflector{}
+
 func Reflect(v any) (string, error) {
-	r := &Reflector{}
+	return defaultReflector.ReflectFromType(v)
+}
 
-	return r.ReflectFromType(v)
+func ReflectSchema(v any) (*AvroSchema, error) {
+	return defaultReflector.ReflectSchema(v)
 }
@@ -1,7 +1,11 @@
 package avroschema
 
 import (
+	"fmt"
+	"image"
+	"math/big"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -139,9 +143,7 @@ func TestArrayOfObject(t *testing.T) {
         }
 	  }},
       {"name": "a_obj_ptr_array_field", "type": {
-	    "type": "array", "items": {
-          "name": "Foo", "type": "Foo"
-        }
+	    "type": "array", "items": "Foo"
 	  }}
     ]
   }`
@@ -324,7 +326,7 @@ func TestUnionPrimitiveType(t *testing.T) {
     "name": "Entity",
     "type": "record",
     "fields": [
-      {"name": "union_field", "type": ["null", "int"]}
+      {"name": "union_field", "type": ["null", "int"], "default": null}
     ]
   }`
 
@@ -349,7 +351,7 @@ func TestUnionRecordType(t *testing.T) {
     "fields": [
       {"name": "union_field", "type": ["null", {
         "name": "Foo", "type": "record", "fields": [{"name": "bar", "type": "string"}]
-      }]}
+      }], "default": null}
     ]
   }`
 
@@ -371,7 +373,7 @@ func TestUnionArrayType(t *testing.T) {
     "fields": [
       {"name": "union_array_field", "type": ["null", {
         "type": "array", "items": "int"
-      }]}
+      }], "default": null}
     ]
   }`
 
@@ -395,11 +397,11 @@ func TestBackwardTransitive(t *testing.T) {
     "name": "Entity",
     "type": "record",
     "fields": [
-      {"name": "a_str_field", "type": ["null", "string"]},
-      {"name": "a_int_field", "type": ["null", "int"]},
-      {"name": "a_bool_field", "type": ["null", "boolean"]},
-      {"name": "a_float_field", "type": ["null", "float"]},
-      {"name": "a_double_field", "type": ["null", "double"]}
+      {"name": "a_str_field", "type": ["null", "string"], "default": null},
+      {"name": "a_int_field", "type": ["null", "int"], "default": null},
+      {"name": "a_bool_field", "type": ["null", "boolean"], "default": null},
+      {"name": "a_float_field", "type": ["null", "float"], "default": null},
+      {"name": "a_double_field", "type": ["null", "double"], "default": null}
     ]
   }`
 
@@ -455,14 +457,7 @@ func TestInlineRecordType(t *testing.T) {
           "name": "Foo", "type": "record", "fields": [{"name": "bar", "type": "string"}]
         }
       },
-	  {"name": "embedded_opt", "type":
-	    [
-		  "null",
-	      {
-            "name": "Foo", "type": "Foo"
-          }
-		]
-      }
+	  {"name": "embedded_opt", "type": ["null", "Foo"], "default": null}
     ]
   }`
 
@@ -688,3 +683,1236 @@ func TestNameMapping(t *testing.T) {
 	assert.Nil(t, err)
 	assert.JSONEq(t, expected, result)
 }
+
+func TestStrictUnsupportedType(t *testing.T) {
+	type Item struct {
+		Price complex128 `json:"price"`
+	}
+	type Order struct {
+		Items []Item `json:"items"`
+	}
+
+	// lenient by default
+	r, err := Reflect(Order{})
+	assert.Nil(t, err)
+	assert.Contains(t, r, `"name":"price","type":"string"`)
+
+	reflector := &Reflector{Strict: true}
+	_, err = reflector.Reflect(Order{})
+
+	var unsupported *UnsupportedTypeError
+	assert.ErrorAs(t, err, &unsupported)
+	assert.Equal(t, "Order.items[].price", unsupported.Path)
+	assert.Equal(t, reflect.TypeOf(complex128(0)), unsupported.Type)
+}
+
+func TestStrictUnsupportedKinds(t *testing.T) {
+	var tdata = []struct {
+		name  string
+		input any
+		path  string
+	}{
+		{"chan", struct {
+			F chan int `json:"f"`
+		}{}, "Record.f"},
+		{"func", struct {
+			F func() `json:"f"`
+		}{}, "Record.f"},
+		{"interface", struct {
+			F map[string]any `json:"f"`
+		}{}, "Record.f{}"},
+		{"map key", struct {
+			F map[int]string `json:"f"`
+		}{}, "Record.f"},
+	}
+
+	reflector := &Reflector{Strict: true}
+	for _, tt := range tdata {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := reflector.Reflect(tt.input)
+			var unsupported *UnsupportedTypeError
+			assert.ErrorAs(t, err, &unsupported)
+			assert.Equal(t, tt.path, unsupported.Path)
+		})
+	}
+}
+
+func TestStrictMapperResult(t *testing.T) {
+	type Entity struct {
+		Field int `json:"field"`
+	}
+
+	reflector := &Reflector{Strict: true}
+	reflector.Mapper = func(t reflect.Type) any {
+		if t.Kind() == reflect.Int {
+			return 42
+		}
+		return nil
+	}
+
+	_, err := reflector.Reflect(Entity{})
+	var invalid *InvalidMapperResultError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "Entity.field", invalid.Path)
+	assert.Equal(t, 42, invalid.Result)
+}
+
+func TestAvroTag(t *testing.T) {
+	type Entity struct {
+		Price    int     `json:"price" avro:"unit_price,default=0,doc=Unit price\\, in cents,alias=price,order=descending"`
+		Currency string  `json:"currency" avro:",default=EUR"`
+		Discount float64 `avro:"discount,optional,default=0.5"`
+		Comment  *string `json:"comment,omitempty" avro:"comment"`
+		Note     string  `avro:"note,optional"`
+		Internal string  `json:"internal" avro:"-"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "unit_price", "type": "int", "default": 0, "doc": "Unit price, in cents", "aliases": ["price"], "order": "descending"},
+      {"name": "currency", "type": "string", "default": "EUR"},
+      {"name": "discount", "type": ["double", "null"], "default": 0.5},
+      {"name": "comment", "type": "string"},
+      {"name": "note", "type": ["null", "string"], "default": null}
+    ]
+  }`
+
+	r, err := Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+
+func TestInvalidAvroTag(t *testing.T) {
+	type Invalid struct {
+		Count int `avro:"count,default=many"`
+	}
+	type Null struct {
+		Count int `avro:"count,default=null"`
+	}
+	type Mismatch struct {
+		Count int `avro:"count,default=\"oops\""`
+	}
+	type Fraction struct {
+		Count int `avro:"count,default=1.5"`
+	}
+	type Item struct {
+		Name string `json:"name"`
+	}
+	type Record struct {
+		Item Item `avro:"item,default={\"name\": 1}"`
+	}
+
+	tests := []struct {
+		name string
+		v    any
+		path string
+	}{
+		{"invalid JSON", Invalid{}, "Invalid.Count"},
+		{"null default of a required field", Null{}, "Null.count"},
+		{"string default of an int field", Mismatch{}, "Mismatch.count"},
+		{"fractional default of an int field", Fraction{}, "Fraction.count"},
+		{"mismatched record field", Record{}, "Record.item"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Reflect(tt.v)
+			var invalid *InvalidTagError
+			assert.ErrorAs(t, err, &invalid)
+			assert.Equal(t, tt.path, invalid.Path)
+		})
+	}
+}
+
+func TestAvroTagRecordDefault(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+	type Entity struct {
+		Item  Item  `avro:"item,default={\"name\": \"none\"}"`
+		Other Item  `avro:"other,default={\"name\": \"other\"}"`
+		Tags  []int `avro:"tags,default=[1\\,2]"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "item", "type": {"name": "Item", "type": "record", "fields": [{"name": "name", "type": "string"}]}, "default": {"name": "none"}},
+      {"name": "other", "type": "Item", "default": {"name": "other"}},
+      {"name": "tags", "type": {"type": "array", "items": "int"}, "default": [1, 2]}
+    ]
+  }`
+
+	r, err := Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+
+func TestBytesAndFixed(t *testing.T) {
+	type Hash [32]byte
+	type Entity struct {
+		Payload  []byte   `json:"payload"`
+		Checksum Hash     `json:"checksum"`
+		Previous *Hash    `json:"previous,omitempty"`
+		ID       [16]byte `json:"id"`
+		ParentID [16]byte `json:"parent_id"`
+		Chunks   [][]byte `json:"chunks"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "payload", "type": "bytes"},
+      {"name": "checksum", "type": {"name": "Hash", "type": "fixed", "size": 32}},
+      {"name": "previous", "type": ["null", "Hash"], "default": null},
+      {"name": "id", "type": {"name": "fixed_16", "type": "fixed", "size": 16}},
+      {"name": "parent_id", "type": "fixed_16"},
+      {"name": "chunks", "type": {"type": "array", "items": "bytes"}}
+    ]
+  }`
+
+	r, err := Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+
+type Suit string
+
+func (Suit) AvroEnumSymbols() []string { return []string{"SPADES", "HEARTS", "DIAMONDS", "CLUBS"} }
+
+type Status int
+
+func (*Status) AvroEnumSymbols() []string { return []string{"UNKNOWN", "ACTIVE", "CLOSED"} }
+func (*Status) AvroEnumDefault() string   { return "UNKNOWN" }
+
+func TestEnumType(t *testing.T) {
+	type Color string
+	type Entity struct {
+		Suit      Suit    `json:"suit"`
+		Trump     *Suit   `json:"trump,omitempty"`
+		Status    Status  `json:"status"`
+		Color     Color   `json:"color"`
+		Palette   []Color `json:"palette"`
+		PlainText string  `json:"plain_text"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "suit", "type": {"name": "Suit", "type": "enum", "symbols": ["SPADES", "HEARTS", "DIAMONDS", "CLUBS"]}},
+      {"name": "trump", "type": ["null", "Suit"], "default": null},
+      {"name": "status", "type": {"name": "Status", "type": "enum", "symbols": ["UNKNOWN", "ACTIVE", "CLOSED"], "default": "UNKNOWN"}},
+      {"name": "color", "type": {"name": "Color", "type": "enum", "symbols": ["RED", "GREEN"], "default": "RED"}},
+      {"name": "palette", "type": {"type": "array", "items": "Color"}},
+      {"name": "plain_text", "type": "string"}
+    ]
+  }`
+
+	reflector := &Reflector{
+		Enums: map[reflect.Type]EnumSpec{
+			reflect.TypeOf(Color("")): {Symbols: []string{"RED", "GREEN"}, Default: "RED"},
+		},
+	}
+	r, err := reflector.Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+
+func TestInvalidEnum(t *testing.T) {
+	type Color string
+	type Entity struct {
+		Color Color `json:"color"`
+	}
+
+	var tdata = []struct {
+		name string
+		spec EnumSpec
+	}{
+		{"no symbols", EnumSpec{}},
+		{"invalid symbol", EnumSpec{Symbols: []string{"light-blue"}}},
+		{"duplicate symbol", EnumSpec{Symbols: []string{"RED", "RED"}}},
+		{"unknown default", EnumSpec{Symbols: []string{"RED"}, Default: "BLUE"}},
+	}
+
+	for _, tt := range tdata {
+		t.Run(tt.name, func(t *testing.T) {
+			reflector := &Reflector{Enums: map[reflect.Type]EnumSpec{reflect.TypeOf(Color("")): tt.spec}}
+			_, err := reflector.Reflect(Entity{})
+			var invalid *InvalidEnumError
+			assert.ErrorAs(t, err, &invalid)
+			assert.Equal(t, "Entity.color", invalid.Path)
+		})
+	}
+}
+
+func TestInterfaceEnum(t *testing.T) {
+	// interfaces are no enums, even with AvroEnumSymbols in their method set
+	type Entity struct {
+		Kind AvroEnum `json:"kind"`
+	}
+
+	r, err := Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"name": "Entity", "type": "record", "fields": [{"name": "kind", "type": "string"}]}`, r)
+
+	_, err = (&Reflector{Strict: true}).Reflect(Entity{})
+	var unsupported *UnsupportedTypeError
+	assert.ErrorAs(t, err, &unsupported)
+}
+
+func TestDecimalType(t *testing.T) {
+	type Entity struct {
+		Amount   *big.Rat   `json:"amount" avro:",precision=12,scale=2"`
+		Rate     big.Float  `json:"rate"`
+		Price    string     `json:"price" avro:",decimal,precision=9,scale=4,fixed"`
+		Discount string     `json:"discount" avro:",decimal,precision=9,scale=4,fixed"`
+		Total    string     `json:"total" avro:",big-decimal"`
+		History  []*big.Rat `json:"history"`
+		Count    string     `json:"count" avro:",decimal,precision=4,scale=0"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "amount", "type": {"type": "bytes", "logicalType": "decimal", "precision": 12, "scale": 2}},
+      {"name": "rate", "type": {"type": "bytes", "logicalType": "decimal", "precision": 20, "scale": 6}},
+      {"name": "price", "type": {"name": "decimal_9_4", "type": "fixed", "size": 4, "logicalType": "decimal", "precision": 9, "scale": 4}},
+      {"name": "discount", "type": "decimal_9_4"},
+      {"name": "total", "type": {"type": "bytes", "logicalType": "big-decimal"}},
+      {"name": "history", "type": {"type": "array", "items": {"type": "bytes", "logicalType": "decimal", "precision": 20, "scale": 6}}},
+      {"name": "count", "type": {"type": "bytes", "logicalType": "decimal", "precision": 4}}
+    ]
+  }`
+
+	reflector := &Reflector{DecimalPrecision: 20, DecimalScale: 6}
+	r, err := reflector.Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+
+	reflector = &Reflector{BigDecimal: true}
+	r, err = reflector.Reflect(struct {
+		Rate big.Float `json:"rate"`
+	}{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"name": "Record", "type": "record", "fields": [{"name": "rate", "type": {"type": "bytes", "logicalType": "big-decimal"}}]}`, r)
+}
+
+func TestInvalidDecimal(t *testing.T) {
+	type Entity struct {
+		Amount big.Rat `json:"amount"`
+	}
+
+	_, err := Reflect(Entity{})
+	var invalid *InvalidTagError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "Entity.amount", invalid.Path)
+
+	reflector := &Reflector{DecimalPrecision: 4, DecimalScale: 6}
+	_, err = reflector.Reflect(Entity{})
+	assert.ErrorAs(t, err, &invalid)
+
+	// only strings, numbers and big.Rat/big.Float hold decimals
+	type Flagged struct {
+		Paid bool `json:"paid" avro:",decimal,precision=4"`
+	}
+	_, err = Reflect(Flagged{})
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "Flagged.paid", invalid.Path)
+	assert.Equal(t, "decimal does not apply to bool", invalid.Reason)
+}
+
+func TestTimePrecision(t *testing.T) {
+	type Entity struct {
+		CreatedAt time.Time     `json:"created_at"`
+		UpdatedAt time.Time     `json:"updated_at" avro:",logicalType=timestamp-millis"`
+		Birthday  time.Time     `json:"birthday" avro:",logicalType=date"`
+		Day       Date          `json:"day"`
+		OpensAt   TimeOfDay     `json:"opens_at"`
+		Timeout   time.Duration `json:"timeout"`
+		Retry     time.Duration `json:"retry" avro:",logicalType=duration"`
+		Stamp     int64         `json:"stamp" avro:",logicalType=timestamp-micros"`
+		Days      int32         `json:"days" avro:",logicalType=date"`
+		History   []int64       `json:"history" avro:",logicalType=timestamp-millis"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "created_at", "type": {"type": "long", "logicalType": "local-timestamp-micros"}},
+      {"name": "updated_at", "type": "long", "logicalType": "timestamp-millis"},
+      {"name": "birthday", "type": {"type": "int", "logicalType": "date"}},
+      {"name": "day", "type": {"type": "int", "logicalType": "date"}},
+      {"name": "opens_at", "type": {"type": "long", "logicalType": "time-micros"}},
+      {"name": "timeout", "type": "long"},
+      {"name": "retry", "type": {"name": "duration", "type": "fixed", "size": 12, "logicalType": "duration"}},
+      {"name": "stamp", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+      {"name": "days", "type": {"type": "int", "logicalType": "date"}},
+      {"name": "history", "type": {"type": "array", "items": {"type": "long", "logicalType": "timestamp-millis"}}}
+    ]
+  }`
+
+	reflector := &Reflector{TimePrecision: Micros, LocalTimestamps: true}
+	r, err := reflector.Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+
+func TestTimeDefaults(t *testing.T) {
+	type Entity struct {
+		CreatedAt time.Time     `json:"created_at"`
+		OpensAt   TimeOfDay     `json:"opens_at"`
+		Timeout   time.Duration `json:"timeout"`
+		Retry     time.Duration `json:"retry"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "created_at", "type": {"type": "long", "logicalType": "timestamp-nanos"}},
+      {"name": "opens_at", "type": {"type": "long", "logicalType": "time-micros"}},
+      {"name": "timeout", "type": {"name": "duration", "type": "fixed", "size": 12, "logicalType": "duration"}},
+      {"name": "retry", "type": "duration"}
+    ]
+  }`
+
+	reflector := &Reflector{TimePrecision: Nanos, DurationAsFixed: true}
+	r, err := reflector.Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+
+	for _, v := range []any{
+		struct {
+			OpensAt TimeOfDay `json:"opens_at" avro:",logicalType=timestamp-millis"`
+		}{},
+		struct {
+			Stamp int32 `json:"stamp" avro:",logicalType=timestamp-micros"`
+		}{},
+		struct {
+			Stamp string `json:"stamp" avro:",logicalType=timestamp-micros"`
+		}{},
+		struct {
+			Stamp int64 `json:"stamp" avro:",logicalType=duration"`
+		}{},
+	} {
+		_, err = Reflect(v)
+		var invalid *InvalidTagError
+		assert.ErrorAs(t, err, &invalid, "%T", v)
+	}
+}
+
+type UUID [16]byte
+
+func (u UUID) MarshalText() ([]byte, error) { return []byte(fmt.Sprintf("%x", u[:])), nil }
+
+func TestUUIDType(t *testing.T) {
+	type Entity struct {
+		ID        UUID     `json:"id"`
+		ParentID  *UUID    `json:"parent_id,omitempty"`
+		RequestID string   `json:"request_id" avro:",uuid"`
+		TraceID   [16]byte `json:"trace_id" avro:",uuid"`
+		Tags      []string `json:"tags" avro:",uuid"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "id", "type": {"type": "string", "logicalType": "uuid"}},
+      {"name": "parent_id", "type": ["null", {"type": "string", "logicalType": "uuid"}], "default": null},
+      {"name": "request_id", "type": {"type": "string", "logicalType": "uuid"}},
+      {"name": "trace_id", "type": {"type": "string", "logicalType": "uuid"}},
+      {"name": "tags", "type": {"type": "array", "items": {"type": "string", "logicalType": "uuid"}}}
+    ]
+  }`
+
+	r, err := Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+
+	expectedFixed := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "id", "type": {"name": "UUID", "type": "fixed", "size": 16, "logicalType": "uuid"}},
+      {"name": "parent_id", "type": ["null", "UUID"], "default": null},
+      {"name": "request_id", "type": {"type": "string", "logicalType": "uuid"}},
+      {"name": "trace_id", "type": {"name": "uuid", "type": "fixed", "size": 16, "logicalType": "uuid"}},
+      {"name": "tags", "type": {"type": "array", "items": {"type": "string", "logicalType": "uuid"}}}
+    ]
+  }`
+
+	reflector := &Reflector{UUIDAsFixed: true}
+	r, err = reflector.Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expectedFixed, r)
+
+	_, err = Reflect(struct {
+		ID int `json:"id" avro:",uuid"`
+	}{})
+	var invalid *InvalidTagError
+	assert.ErrorAs(t, err, &invalid)
+}
+
+func TestRecursiveType(t *testing.T) {
+	type Node struct {
+		Value    int     `json:"value"`
+		Children []*Node `json:"children"`
+		Parent   *Node   `json:"parent,omitempty"`
+	}
+
+	expected := `{
+    "name": "Node",
+    "type": "record",
+    "namespace": "com.example",
+    "fields": [
+      {"name": "value", "type": "int"},
+      {"name": "children", "type": {"type": "array", "items": "com.example.Node"}},
+      {"name": "parent", "type": ["null", "com.example.Node"], "default": null}
+    ]
+  }`
+
+	reflector := &Reflector{Namespace: "com.example"}
+	r, err := reflector.Reflect(Node{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+
+type Employee struct {
+	Name    string      `json:"name"`
+	Manages *Department `json:"manages,omitempty"`
+}
+
+type Department struct {
+	Head    Employee   `json:"head"`
+	Members []Employee `json:"members"`
+}
+
+func TestMutuallyRecursiveType(t *testing.T) {
+	expected := `{
+    "name": "Department",
+    "type": "record",
+    "fields": [
+      {"name": "head", "type": {
+        "name": "Employee", "type": "record", "fields": [
+          {"name": "name", "type": "string"},
+          {"name": "manages", "type": ["null", "Department"], "default": null}
+        ]
+      }},
+      {"name": "members", "type": {"type": "array", "items": "Employee"}}
+    ]
+  }`
+
+	r, err := Reflect(Department{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+
+func billingAddress() any {
+	type Address struct {
+		IBAN string `json:"iban"`
+	}
+	return Address{}
+}
+
+func shippingAddress() any {
+	type Address struct {
+		Street string `json:"street"`
+	}
+	return Address{}
+}
+
+func TestNameCollision(t *testing.T) {
+	entity := reflect.StructOf([]reflect.StructField{
+		{Name: "Billing", Type: reflect.TypeOf(billingAddress()), Tag: `json:"billing"`},
+		{Name: "Shipping", Type: reflect.TypeOf(shippingAddress()), Tag: `json:"shipping"`},
+	})
+
+	_, err := Reflect(reflect.New(entity).Elem().Interface())
+	var collision *NameCollisionError
+	assert.ErrorAs(t, err, &collision)
+	assert.Equal(t, "Address", collision.Name)
+	assert.Equal(t, "Record.shipping", collision.Path)
+}
+
+func TestNamespacer(t *testing.T) {
+	type Canvas struct {
+		Bounds image.Rectangle `json:"bounds"`
+		Origin image.Point     `json:"origin"`
+	}
+
+	expected := `{
+    "name": "Canvas",
+    "type": "record",
+    "namespace": "com.wirelessr.avroschema",
+    "fields": [
+      {"name": "bounds", "type": {
+        "name": "Rectangle", "type": "record", "namespace": "image", "fields": [
+          {"name": "Min", "type": {
+            "name": "Point", "type": "record", "namespace": "image", "fields": [
+              {"name": "X", "type": "int"},
+              {"name": "Y", "type": "int"}
+            ]
+          }},
+          {"name": "Max", "type": "image.Point"}
+        ]
+      }},
+      {"name": "origin", "type": "image.Point"}
+    ]
+  }`
+
+	reflector := &Reflector{EmitAllFields: true, Namespacer: PackageNamespace}
+	r, err := reflector.Reflect(Canvas{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+
+func TestNamespacerSyntheticTypes(t *testing.T) {
+	type A struct {
+		ID     [16]byte `json:"id"`
+		Amount string   `json:"amount" avro:"amount,decimal,fixed,precision=4"`
+		Meta   struct {
+			Source string `json:"source"`
+		} `json:"meta"`
+	}
+	type B struct {
+		ID     [16]byte `json:"id"`
+		Amount string   `json:"amount" avro:"amount,decimal,fixed,precision=4"`
+	}
+	type Root struct {
+		A A `json:"a"`
+		B B `json:"b"`
+	}
+
+	reflector := &Reflector{Namespacer: func(t reflect.Type) string {
+		switch t.Name() {
+		case "A":
+			return "ns.a"
+		case "B":
+			return "ns.b"
+		}
+		return ""
+	}}
+	r, err := reflector.Reflect(Root{})
+	assert.Nil(t, err)
+
+	// names declared within ns.a are not visible from ns.b, unless referred to by full name
+	_, err = ParseSchema([]byte(r))
+	assert.Nil(t, err)
+	schema, err := reflector.ReflectSchema(Root{})
+	assert.Nil(t, err)
+	a := schema.Fields[0].Type.(*AvroSchema)
+	assert.Equal(t, "ns.a", a.Fields[0].Type.(*AvroSchema).Namespace)
+	assert.Equal(t, "ns.a", a.Fields[2].Type.(*AvroSchema).Namespace)
+	b := schema.Fields[1].Type.(*AvroSchema)
+	assert.Equal(t, "ns.b", b.Fields[0].Type.(*AvroSchema).Namespace)
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	Next  int `json:"next"`
+}
+
+func TestGenericAndAnonymousNames(t *testing.T) {
+	type Order struct {
+		ID   string `json:"id"`
+		Meta struct {
+			Source string `json:"source"`
+		} `json:"meta"`
+	}
+	type Entity struct {
+		Orders Page[Order]               `json:"orders"`
+		Pairs  Page[map[string][]*Order] `json:"pairs"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "orders", "type": {"name": "Page_Order", "type": "record", "fields": [
+        {"name": "items", "type": {"type": "array", "items": {"name": "Order", "type": "record", "fields": [
+          {"name": "id", "type": "string"},
+          {"name": "meta", "type": {"name": "Entity_orders_items_meta", "type": "record", "fields": [
+            {"name": "source", "type": "string"}
+          ]}}
+        ]}}},
+        {"name": "next", "type": "int"}
+      ]}},
+      {"name": "pairs", "type": {"name": "Page_map_string_Order", "type": "record", "fields": [
+        {"name": "items", "type": {"type": "array", "items": {"type": "map", "values": {"type": "array", "items": "Order"}}}},
+        {"name": "next", "type": "int"}
+      ]}}
+    ]
+  }`
+
+	r, err := Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+
+func TestNamer(t *testing.T) {
+	type Order struct {
+		ID string `json:"id"`
+	}
+	type Entity struct {
+		Orders Page[Order] `json:"orders"`
+	}
+
+	reflector := &Reflector{
+		Namer: func(t reflect.Type, path string) string {
+			if t == reflect.TypeOf(Page[Order]{}) {
+				return "OrderPage"
+			}
+			return ""
+		},
+	}
+	r, err := reflector.Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.Contains(t, r, `"name":"OrderPage"`)
+	assert.Contains(t, r, `"name":"Order"`)
+
+	reflector.Namer = func(t reflect.Type, path string) string {
+		return "Order-Page"
+	}
+	_, err = reflector.Reflect(Entity{})
+	var invalid *InvalidNameError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "Order-Page", invalid.Name)
+}
+
+func TestInvalidFieldName(t *testing.T) {
+	type Entity struct {
+		FirstName string `json:"first-name"`
+	}
+
+	_, err := Reflect(Entity{})
+	var invalid *InvalidNameError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "Entity.first-name", invalid.Path)
+
+	reflector := &Reflector{Namespace: "com.example-corp"}
+	_, err = reflector.Reflect(struct {
+		Name string `json:"name"`
+	}{})
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "com.example-corp", invalid.Name)
+
+	_, err = Reflect(struct {
+		Name string `avro:"name,alias=old-name"`
+	}{})
+	var invalidTag *InvalidTagError
+	assert.ErrorAs(t, err, &invalidTag)
+	assert.Equal(t, "Record.Name", invalidTag.Path)
+}
+
+type PaymentMethod interface {
+	isPaymentMethod()
+}
+
+type Card struct {
+	Number string `json:"number"`
+}
+
+type BankTransfer struct {
+	IBAN string `json:"iban"`
+}
+
+type Wallet struct {
+	Provider string `json:"provider"`
+}
+
+func (Card) isPaymentMethod()         {}
+func (BankTransfer) isPaymentMethod() {}
+func (*Wallet) isPaymentMethod()      {}
+
+var paymentMethodType = reflect.TypeOf((*PaymentMethod)(nil)).Elem()
+
+func TestInterfaceUnion(t *testing.T) {
+	type Order struct {
+		Payment  PaymentMethod   `json:"payment"`
+		Refund   PaymentMethod   `json:"refund,omitempty"`
+		Attempts []PaymentMethod `json:"attempts"`
+	}
+
+	expected := `{
+    "name": "Order",
+    "type": "record",
+    "fields": [
+      {"name": "payment", "type": [
+        {"name": "Card", "type": "record", "fields": [{"name": "number", "type": "string"}]},
+        {"name": "BankTransfer", "type": "record", "fields": [{"name": "iban", "type": "string"}]},
+        {"name": "Wallet", "type": "record", "fields": [{"name": "provider", "type": "string"}]}
+      ]},
+      {"name": "refund", "type": ["null", "Card", "BankTransfer", "Wallet"], "default": null},
+      {"name": "attempts", "type": {"type": "array", "items": ["Card", "BankTransfer", "Wallet"]}}
+    ]
+  }`
+
+	reflector := &Reflector{
+		Unions: map[reflect.Type]UnionSpec{
+			paymentMethodType: {Members: []reflect.Type{
+				reflect.TypeOf(Card{}), reflect.TypeOf(BankTransfer{}), reflect.TypeOf(Wallet{}),
+			}},
+		},
+	}
+	r, err := reflector.Reflect(Order{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+
+	reflector.Unions[paymentMethodType] = UnionSpec{
+		Members:  []reflect.Type{reflect.TypeOf(Card{})},
+		Nullable: true,
+	}
+	r, err = reflector.Reflect(Order{})
+	assert.Nil(t, err)
+	assert.Contains(t, r, `{"name":"refund","type":["null","Card"],"default":null}`)
+}
+
+func TestInvalidInterfaceUnion(t *testing.T) {
+	type Order struct {
+		Payment PaymentMethod `json:"payment"`
+	}
+
+	var tdata = []struct {
+		name    string
+		members []reflect.Type
+	}{
+		{"no members", nil},
+		{"not implemented", []reflect.Type{reflect.TypeOf(Order{})}},
+		{"duplicate", []reflect.Type{reflect.TypeOf(Card{}), reflect.TypeOf(&Card{})}},
+	}
+
+	for _, tt := range tdata {
+		t.Run(tt.name, func(t *testing.T) {
+			reflector := &Reflector{Unions: map[reflect.Type]UnionSpec{paymentMethodType: {Members: tt.members}}}
+			_, err := reflector.Reflect(Order{})
+			var invalid *InvalidUnionError
+			assert.ErrorAs(t, err, &invalid)
+			assert.Equal(t, "Order.payment", invalid.Path)
+		})
+	}
+}
+
+type jsonBase struct {
+	ID      string `json:"id"`
+	Created int64  `json:"created"`
+}
+
+type JSONAudit struct {
+	Author string
+}
+
+type JSONLabels struct {
+	Author string `json:"Author"`
+	Color  string
+}
+
+type JSONStyle struct {
+	Color string
+}
+
+func TestJSONSemantics(t *testing.T) {
+	type Entity struct {
+		jsonBase   // unexported but embedded, fields are promoted
+		*JSONAudit // promoted through a pointer, Author loses against the tagged one of JSONLabels
+		JSONLabels // Color conflicts with JSONStyle.Color, both untagged: dropped
+		JSONStyle
+		Name   string    `json:"name"`
+		Dash   string    `json:"-"`
+		Count  int       `json:"count,string"`
+		Ratio  *float64  `json:"ratio,string"`
+		Items  []int     `json:"items,string"`
+		secret string    //nolint:unused
+		Nested JSONStyle `json:"nested"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "id", "type": "string"},
+      {"name": "created", "type": "long"},
+      {"name": "Author", "type": "string"},
+      {"name": "name", "type": "string"},
+      {"name": "count", "type": "string"},
+      {"name": "ratio", "type": "string"},
+      {"name": "items", "type": {"type": "array", "items": "int"}},
+      {"name": "nested", "type": {"name": "JSONStyle", "type": "record", "fields": [{"name": "Color", "type": "string"}]}}
+    ]
+  }`
+
+	reflector := &Reflector{EmitAllFields: true}
+	r, err := reflector.Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+func TestJSONShadowing(t *testing.T) {
+	type Inner struct {
+		Name  string `json:"name"`
+		Value int    `json:"value"`
+	}
+	type Outer struct {
+		Inner
+		Name  string `json:"name"`
+		Label string
+	}
+	type Entity struct {
+		Outer
+		Extra  string `json:"extra"`
+		Tagged Inner  `json:"tagged"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "value", "type": "int"},
+      {"name": "name", "type": "string"},
+      {"name": "Label", "type": "string"},
+      {"name": "extra", "type": "string"},
+      {"name": "tagged", "type": {"name": "Inner", "type": "record", "fields": [
+        {"name": "name", "type": "string"},
+        {"name": "value", "type": "int"}
+      ]}}
+    ]
+  }`
+
+	reflector := &Reflector{EmitAllFields: true}
+	r, err := reflector.Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+
+func TestJSONDashName(t *testing.T) {
+	type Entity struct {
+		Dash string `json:"-,"`
+	}
+
+	_, err := Reflect(Entity{})
+	var invalid *InvalidNameError
+	assert.ErrorAs(t, err, &invalid)
+	assert.Equal(t, "-", invalid.Name)
+
+	type Renamed struct {
+		Dash string `json:"-," avro:"dash"`
+	}
+	r, err := Reflect(Renamed{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"name": "Renamed", "type": "record", "fields": [{"name": "dash", "type": "string"}]}`, r)
+}
+
+type Coord struct {
+	X, Y int
+}
+
+func (c Coord) MarshalText() ([]byte, error) { return []byte(fmt.Sprintf("%d,%d", c.X, c.Y)), nil }
+
+func TestNonStringMapKeys(t *testing.T) {
+	type Position struct {
+		Qty int `json:"qty"`
+	}
+	type Positions map[int64]Position
+	type Entity struct {
+		ByID     map[int64]Position     `json:"by_id"`
+		Named    Positions              `json:"named"`
+		Again    map[int64]Position     `json:"again"`
+		ByCoord  map[Coord]string       `json:"by_coord"`
+		ByStruct map[Position]time.Time `json:"by_struct"`
+	}
+
+	expectedEntries := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "by_id", "type": {"type": "array", "items": {"name": "Entity_by_id", "type": "record", "fields": [
+        {"name": "key", "type": "long"},
+        {"name": "value", "type": {"name": "Position", "type": "record", "fields": [{"name": "qty", "type": "int"}]}}
+      ]}}},
+      {"name": "named", "type": {"type": "array", "items": {"name": "Positions", "type": "record", "fields": [
+        {"name": "key", "type": "long"},
+        {"name": "value", "type": "Position"}
+      ]}}},
+      {"name": "again", "type": {"type": "array", "items": "Entity_by_id"}},
+      {"name": "by_coord", "type": {"type": "array", "items": {"name": "Entity_by_coord", "type": "record", "fields": [
+        {"name": "key", "type": {"name": "Coord", "type": "record", "fields": [{"name": "X", "type": "int"}, {"name": "Y", "type": "int"}]}},
+        {"name": "value", "type": "string"}
+      ]}}},
+      {"name": "by_struct", "type": {"type": "array", "items": {"name": "Entity_by_struct", "type": "record", "fields": [
+        {"name": "key", "type": "Position"},
+        {"name": "value", "type": {"type": "long", "logicalType": "timestamp-millis"}}
+      ]}}}
+    ]
+  }`
+
+	reflector := &Reflector{MapKeys: MapKeysEntries, EmitAllFields: true}
+	r, err := reflector.Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expectedEntries, r)
+
+	expectedText := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "by_id", "type": {"type": "map", "values": {"name": "Position", "type": "record", "fields": [{"name": "qty", "type": "int"}]}}},
+      {"name": "named", "type": {"type": "map", "values": "Position"}},
+      {"name": "again", "type": {"type": "map", "values": "Position"}},
+      {"name": "by_coord", "type": {"type": "map", "values": "string"}},
+      {"name": "by_struct", "type": "string"}
+    ]
+  }`
+
+	reflector = &Reflector{MapKeys: MapKeysText}
+	r, err = reflector.Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expectedText, r)
+
+	reflector.Strict = true
+	_, err = reflector.Reflect(Entity{})
+	var unsupported *UnsupportedTypeError
+	assert.ErrorAs(t, err, &unsupported)
+	assert.Equal(t, "Entity.by_struct", unsupported.Path)
+}
+
+func TestNullablePointers(t *testing.T) {
+	type Foo struct {
+		Bar string `json:"bar"`
+	}
+	type Entity struct {
+		Name    *string           `json:"name"`
+		Count   int               `json:"count"`
+		Tags    []string          `json:"tags"`
+		Attrs   map[string]string `json:"attrs"`
+		Foo     *Foo              `json:"foo"`
+		Payload []byte            `json:"payload"`
+		Level   *int              `json:"level" avro:",default=3"`
+		Hint    *string           `json:"hint" avro:",default=null"`
+	}
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "fields": [
+      {"name": "name", "type": ["null", "string"], "default": null},
+      {"name": "count", "type": "int"},
+      {"name": "tags", "type": ["null", {"type": "array", "items": "string"}], "default": null},
+      {"name": "attrs", "type": ["null", {"type": "map", "values": "string"}], "default": null},
+      {"name": "foo", "type": ["null", {"name": "Foo", "type": "record", "fields": [{"name": "bar", "type": "string"}]}], "default": null},
+      {"name": "payload", "type": ["null", "bytes"], "default": null},
+      {"name": "level", "type": ["int", "null"], "default": 3},
+      {"name": "hint", "type": ["null", "string"], "default": null}
+    ]
+  }`
+
+	reflector := &Reflector{NullablePointers: true}
+	r, err := reflector.Reflect(Entity{})
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+}
+
+func TestReflectSchema(t *testing.T) {
+	type Foo struct {
+		Bar string `json:"bar"`
+	}
+	type Entity struct {
+		Name string `json:"name"`
+		Foo  Foo    `json:"foo"`
+	}
+
+	s, err := ReflectSchema(&Entity{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Entity", s.Name)
+	assert.Equal(t, "record", s.Type)
+	assert.Len(t, s.Fields, 2)
+	assert.Equal(t, &AvroSchema{Name: "name", Type: "string"}, s.Fields[0])
+
+	// post-process before serializing
+	s.Doc = "An entity"
+	s.Fields[1].Type.(*AvroSchema).Namespace = "com.example"
+
+	expected := `{
+    "name": "Entity",
+    "type": "record",
+    "doc": "An entity",
+    "fields": [
+      {"name": "name", "type": "string"},
+      {"name": "foo", "type": {"name": "Foo", "type": "record", "namespace": "com.example", "fields": [{"name": "bar", "type": "string"}]}}
+    ]
+  }`
+
+	r, err := StructToJson(s)
+	assert.Nil(t, err)
+	assert.JSONEq(t, expected, r)
+
+	reflector := &Reflector{Strict: true}
+	_, err = reflector.ReflectSchema(struct {
+		F chan int `json:"f"`
+	}{})
+	assert.NotNil(t, err)
+}
+
+func TestReflectInvalidRoot(t *testing.T) {
+	var root *InvalidRootError
+	_, err := ReflectSchema(42)
+	assert.ErrorAs(t, err, &root)
+	assert.Equal(t, reflect.TypeOf(0), root.Type)
+	assert.EqualError(t, err, "avroschema: cannot reflect int, a struct is expected")
+
+	_, err = Reflect([]string{})
+	assert.ErrorAs(t, err, &root)
+
+	_, err = ReflectSchema(nil)
+	assert.EqualError(t, err, "avroschema: cannot reflect nil, a struct is expected")
+}
+
+func TestReflectorCache(t *testing.T) {
+	type Entity struct {
+		Name   string            `json:"name"`
+		Tags   []string          `json:"tags" avro:",default=[\"a\"]"`
+		Ratio  float64           `json:"ratio"`
+		Labels map[string]string `json:"labels" avro:",default={\"k\": \"v\"}"`
+	}
+
+	reflector := new(Reflector)
+	first, err := reflector.ReflectSchema(&Entity{})
+	assert.Nil(t, err)
+
+	// modifying the returned schema leaves the cached one alone, defaults included
+	first.Name = "Changed"
+	first.Fields[1].Type.(*AvroSchema).Items = "int"
+	first.Fields[1].Default.([]any)[0] = "b"
+	first.Fields[3].Default.(map[string]any)["k"] = "w"
+
+	second, err := reflector.ReflectSchema(&Entity{})
+	assert.Nil(t, err)
+	assert.Equal(t, "Entity", second.Name)
+	assert.Equal(t, "string", second.Fields[1].Type.(*AvroSchema).Items)
+	assert.Equal(t, []any{"a"}, second.Fields[1].Default)
+	assert.Equal(t, map[string]any{"k": "v"}, second.Fields[3].Default)
+
+	// changing an option reflects the type anew
+	reflector.NameMapping = map[string]string{"Entity": "Renamed"}
+	r, err := reflector.Reflect(&Entity{})
+	assert.Nil(t, err)
+	assert.Contains(t, r, `"name":"Renamed"`)
+
+	// and so does changing a registered map in place
+	reflector.NameMapping["Entity"] = "Again"
+	r, err = reflector.Reflect(&Entity{})
+	assert.Nil(t, err)
+	assert.Contains(t, r, `"name":"Again"`)
+
+	// closures of the same literal are told apart
+	namer := func(name string) func(reflect.Type, string) string {
+		return func(reflect.Type, string) string { return name }
+	}
+	reflector.NameMapping = nil
+	reflector.Namer = namer("First")
+	r, err = reflector.Reflect(&Entity{})
+	assert.Nil(t, err)
+	assert.Contains(t, r, `"name":"First"`)
+
+	reflector.Namer = namer("Second")
+	r, err = reflector.Reflect(&Entity{})
+	assert.Nil(t, err)
+	assert.Contains(t, r, `"name":"Second"`)
+}
+
+func TestReflectorConcurrent(t *testing.T) {
+	type Item struct {
+		SKU   string  `json:"sku"`
+		Price float64 `json:"price"`
+	}
+	type Order struct {
+		ID    string `json:"id"`
+		Items []Item `json:"items"`
+	}
+	type Customer struct {
+		Name   string  `json:"name"`
+		Orders []Order `json:"orders"`
+	}
+
+	reflector := &Reflector{Namespace: "com.example"}
+	expectedOrder, err := (&Reflector{Namespace: "com.example"}).Reflect(&Order{})
+	assert.Nil(t, err)
+	expectedCustomer, err := (&Reflector{Namespace: "com.example"}).Reflect(&Customer{})
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				r, err := reflector.Reflect(&Order{})
+				assert.Nil(t, err)
+				assert.JSONEq(t, expectedOrder, r)
+
+				s, err := reflector.ReflectSchema(&Customer{})
+				assert.Nil(t, err)
+				s.Doc = "modified by every goroutine"
+				r, err = reflector.Reflect(&Customer{})
+				assert.Nil(t, err)
+				assert.JSONEq(t, expectedCustomer, r)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type benchmarkOrder struct {
+	ID       string            `json:"id"`
+	Customer Employee          `json:"customer"`
+	Items    []benchmarkItem   `json:"items"`
+	Labels   map[string]string `json:"labels"`
+	Placed   time.Time         `json:"placed"`
+	Note     *string           `json:"note,omitempty"`
+}
+
+type benchmarkItem struct {
+	SKU      string  `json:"sku"`
+	Quantity int     `json:"quantity"`
+	Price    float64 `json:"price"`
+}
+
+func BenchmarkReflect(b *testing.B) {
+	reflector := new(Reflector)
+	for i := 0; i < b.N; i++ {
+		if _, err := reflector.Reflect(&benchmarkOrder{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReflectSchema(b *testing.B) {
+	reflector := new(Reflector)
+	for i := 0; i < b.N; i++ {
+		if _, err := reflector.ReflectSchema(&benchmarkOrder{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReflectUncached(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := new(Reflector).Reflect(&benchmarkOrder{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReflectParallel(b *testing.B) {
+	reflector := new(Reflector)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := reflector.Reflect(&benchmarkOrder{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package avroschema
+
+import "strings"
+
+var primitiveTypes = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"int":     true,
+	"long":    true,
+	"float":   true,
+	"double":  true,
+	"bytes":   true,
+	"string":  true,
+}
+
+func isNamedType(typ any) bool {
+	switch typ {
+	case "record", "error", "enum", "fixed":
+		return true
+	}
+	return false
+}
+
+/*
+A named type along with the namespace that names within it are relative to.
+*/
+type namedSchema struct {
+	schema    *AvroSchema
+	namespace string
+}
+
+/*
+The named types of a schema by their full name, for following references.
+*/
+type namedSchemas map[string]namedSchema
+
+/*
+Full name and namespace of a named type declared within the enclosing namespace.
+A dotted name is a full name already, otherwise the namespace attribute or else the enclosing one applies.
+*/
+func fullName(name, namespace, enclosing string) (string, string) {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name, name[:i]
+	}
+	if namespace == "" {
+		namespace = enclosing
+	}
+	return qualify(namespace, name), namespace
+}
+
+/*
+Collect every named type of the schema s, e.g. one returned by ReflectSchema or ParseSchema.
+*/
+func indexSchema(s any) namedSchemas {
+	names := namedSchemas{}
+	names.index(s, "")
+	return names
+}
+
+func (n namedSchemas) index(s any, namespace string) {
+	switch s := s.(type) {
+	case []any:
+		for _, b := range s {
+			n.index(b, namespace)
+		}
+	case []*AvroSchema:
+		for _, f := range s {
+			n.index(f, namespace)
+		}
+	case *AvroSchema:
+		if s == nil {
+			return
+		}
+		if isNamedType(s.Type) {
+			var full string
+			full, namespace = fullName(s.Name, s.Namespace, namespace)
+			if _, ok := n[full]; ok {
+				return
+			}
+			n[full] = namedSchema{schema: s, namespace: namespace}
+		}
+		n.index(s.Type, namespace)
+		n.index(s.Items, namespace)
+		n.index(s.Values, namespace)
+		for _, f := range s.Fields {
+			n.index(fieldType(f), namespace)
+		}
+	}
+}
+
+/*
+Look up the named type referred to by name within namespace.
+*/
+func (n namedSchemas) lookup(name, namespace string) (namedSchema, bool) {
+	if !strings.Contains(name, ".") && namespace != "" {
+		if named, ok := n[namespace+"."+name]; ok {
+			return named, true
+		}
+	}
+	named, ok := n[name]
+	return named, ok
+}
+
+/*
+Follow references until s is a primitive name, a union or a schema which is not a mere reference.
+Return it along with the namespace that names within it are relative to, or false for an undefined name.
+*/
+func (n namedSchemas) resolve(s any, namespace string) (any, string, bool) {
+	for {
+		switch v := s.(type) {
+		case string:
+			if primitiveTypes[v] {
+				return v, namespace, true
+			}
+			named, ok := n.lookup(v, namespace)
+			if !ok {
+				return nil, namespace, false
+			}
+			return named.schema, named.namespace, true
+		case *AvroSchema:
+			if isNamedType(v.Type) {
+				_, ns := fullName(v.Name, v.Namespace, namespace)
+				return v, ns, true
+			}
+			// a wrapped reference or union, e.g. {"type": "Foo"}
+			switch t := v.Type.(type) {
+			case string:
+				if !primitiveTypes[t] && t != "array" && t != "map" {
+					s = t
+					continue
+				}
+			case []any, *AvroSchema:
+				s = t
+				continue
+			}
+			return v, namespace, true
+		default:
+			return s, namespace, true
+		}
+	}
+}
+
+/*
+The schema of a record field. The Reflector flattens timestamp-millis into the field, e.g.
+{"name":"ts","type":"long","logicalType":"timestamp-millis"}, which is unwrapped here.
+*/
+func fieldType(f *AvroSchema) any {
+	if t, ok := f.Type.(string); ok && primitiveTypes[t] && f.LogicalType != "" {
+		return &AvroSchema{Type: t, LogicalType: f.LogicalType, Precision: f.Precision, Scale: f.Scale}
+	}
+	return f.Type
+}
+
+/*
+The kind of a resolved schema, for messages.
+*/
+func schemaKind(s any) string {
+	switch v := s.(type) {
+	case string:
+		return v
+	case []any:
+		return "union"
+	case *AvroSchema:
+		if t, ok := v.Type.(string); ok {
+			if isNamedType(t) {
+				return t + " " + v.Name
+			}
+			return t
+		}
+	}
+	return "schema"
+}
@@ -13,6 +13,20 @@ type AvroSchema struct {
 	Aliases     []string      `json:"aliases,omitempty"`
 	Default     any           `json:"default,omitempty"`
 	LogicalType string        `json:"logicalType,omitempty"`
+	Order       string        `json:"order,omitempty"`
+	Size        int           `json:"size,omitempty"`
+	Symbols     []string      `json:"symbols,omitempty"`
+	Precision   int           `json:"precision,omitempty"`
+	Scale       int           `json:"scale,omitempty"`
+}
+
+/*
+Null is an explicit null default, e.g. of an optional field, as opposed to a nil Default meaning no default at all.
+*/
+type Null struct{}
+
+func (Null) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
 }
 
 func StructToJson(data any) (string, error) {
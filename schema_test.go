@@ -28,3 +28,13 @@ func TestStructArrayOfObject(t *testing.T) {
 	assert.JSONEq(t, expected, ret)
 	assert.Nil(t, err)
 }
+
+func TestNullDefault(t *testing.T) {
+	noDefault, err := StructToJson(AvroSchema{Name: "f", Type: []any{"null", "int"}})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"name": "f", "type": ["null", "int"]}`, noDefault)
+
+	nullDefault, err := StructToJson(AvroSchema{Name: "f", Type: []any{"null", "int"}, Default: Null{}})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"name": "f", "type": ["null", "int"], "default": null}`, nullDefault)
+}
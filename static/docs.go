@@ -0,0 +1,124 @@
+package static
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/wirelessr/avroschema"
+)
+
+/*
+ParseDocs reads the doc comments of the types declared in the Go package in dir, and of their
+struct fields, falling back to the line comment of a field, into a DocRegistry for Reflector.Docs.
+It runs the go command, in dir, to locate the package.
+*/
+func ParseDocs(dir string) (avroschema.DocRegistry, error) {
+	out, err := goList(dir)
+	if err != nil {
+		return nil, err
+	}
+	p := &listedPackage{}
+	if err := json.Unmarshal(out, p); err != nil {
+		return nil, fmt.Errorf("avroschema: go list: %w", err)
+	}
+	if p.Error != nil {
+		return nil, fmt.Errorf("avroschema: loading %s: %s", p.ImportPath, p.Error.Err)
+	}
+
+	pkgPath := p.ImportPath
+	if p.Name == "main" {
+		pkgPath = "main"
+	}
+
+	ret := avroschema.DocRegistry{}
+	fset := token.NewFileSet()
+	for _, name := range append(p.GoFiles, p.CgoFiles...) {
+		f, err := parser.ParseFile(fset, filepath.Join(p.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("avroschema: loading %s: %w", p.ImportPath, err)
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				doc := ts.Doc
+				// the doc of an unparenthesized declaration belongs to the declaration
+				if doc == nil && !gen.Lparen.IsValid() {
+					doc = gen.Doc
+				}
+				key := pkgPath + "." + ts.Name.Name
+				addDoc(ret, key, doc)
+
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, field := range st.Fields.List {
+					doc := field.Doc
+					if doc == nil {
+						doc = field.Comment
+					}
+					names := field.Names
+					if len(names) == 0 {
+						names = []*ast.Ident{embeddedName(field.Type)}
+					}
+					for _, name := range names {
+						if name != nil {
+							addDoc(ret, key+"."+name.Name, doc)
+						}
+					}
+				}
+			}
+		}
+	}
+	return ret, nil
+}
+
+func addDoc(d avroschema.DocRegistry, key string, doc *ast.CommentGroup) {
+	if text := docText(doc); text != "" {
+		d[key] = text
+	}
+}
+
+/*
+The text of a comment, without directives such as //go:generate, and with the lines of a paragraph joined.
+*/
+func docText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	var paragraphs []string
+	for _, p := range strings.Split(doc.Text(), "\n\n") {
+		if p = strings.Join(strings.Fields(p), " "); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
+
+/*
+The name of an embedded field, i.e. of its type without pointer, package and type arguments.
+*/
+func embeddedName(expr ast.Expr) *ast.Ident {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel
+	case *ast.IndexExpr:
+		return embeddedName(e.X)
+	case *ast.IndexListExpr:
+		return embeddedName(e.X)
+	}
+	return nil
+}
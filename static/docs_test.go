@@ -0,0 +1,47 @@
+package static
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wirelessr/avroschema"
+)
+
+func TestParseDocs(t *testing.T) {
+	docs, err := ParseDocs("testdata/event")
+	assert.Nil(t, err)
+	assert.Equal(t, avroschema.DocRegistry{
+		"main.Event":      "Event is something that happened, as told by its source.\n\nIt is never changed.",
+		"main.Event.Name": "Name is unique per source.",
+		"main.Event.Kind": "what happened",
+		"main.Payload":    "Payload is opaque.",
+		"main.Kind":       "Kind of an event.",
+	}, docs)
+
+	_, err = ParseDocs("testdata/missing")
+	assert.NotNil(t, err)
+}
+
+func TestReflectDocs(t *testing.T) {
+	pkg, err := Load("testdata/event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := ParseDocs("testdata/event")
+	assert.Nil(t, err)
+
+	schema, err := Reflect(&avroschema.Reflector{Docs: docs}, pkg, "Event")
+	assert.Nil(t, err)
+	assert.Equal(t, &avroschema.AvroSchema{
+		Name: "Event",
+		Type: "record",
+		Doc:  "Event is something that happened, as told by its source.\n\nIt is never changed.",
+		Fields: []*avroschema.AvroSchema{
+			{Name: "name", Type: "string", Doc: "Name is unique per source."},
+			{Name: "kind", Type: &avroschema.AvroSchema{Name: "Kind", Type: "enum", Doc: "Kind of an event.", Symbols: []string{"CREATED", "DELETED"}}, Doc: "what happened"},
+			{Name: "payload", Type: &avroschema.AvroSchema{Name: "Payload", Type: "record", Doc: "Payload is opaque.", Fields: []*avroschema.AvroSchema{
+				{Name: "data", Type: "bytes"},
+			}}, Doc: "raw payload"},
+		},
+	}, schema)
+}
@@ -0,0 +1,205 @@
+package static
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os/exec"
+	"path/filepath"
+)
+
+/*
+Package is a Go package type-checked from source by Load, for Reflect.
+*/
+type Package struct {
+	Types *types.Package
+	fset  *token.FileSet
+	info  *types.Info
+	files map[string]*ast.File // the syntax of every package loaded from source, by file name
+}
+
+/*
+The output of `go list -json` needed to load a package.
+*/
+type listedPackage struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	GoFiles    []string
+	CgoFiles   []string
+	ImportMap  map[string]string
+	Standard   bool
+	Error      *struct{ Err string }
+}
+
+/*
+Run `go list -json` on the package in dir, with the given flags.
+*/
+func goList(dir string, flags ...string) ([]byte, error) {
+	cmd := exec.Command("go", append(append([]string{"list", "-json"}, flags...), ".")...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("avroschema: go list: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return out, nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+/*
+Load type-checks the Go package in dir from source, along with the packages it imports,
+so that Reflect can reflect its types without compiling it. The standard library is read
+from export data instead. It runs the go command, in dir, to locate the packages.
+*/
+func Load(dir string) (*Package, error) {
+	out, err := goList(dir, "-deps")
+	if err != nil {
+		return nil, err
+	}
+
+	listed := map[string]*listedPackage{}
+	var root *listedPackage
+	for dec := json.NewDecoder(bytes.NewReader(out)); dec.More(); {
+		p := &listedPackage{}
+		if err := dec.Decode(p); err != nil {
+			return nil, fmt.Errorf("avroschema: go list: %w", err)
+		}
+		listed[p.ImportPath] = p
+		// dependencies come first
+		root = p
+	}
+
+	fset := token.NewFileSet()
+	l := &loader{
+		Package: Package{
+			fset: fset,
+			info: &types.Info{
+				Types: map[ast.Expr]types.TypeAndValue{},
+				Defs:  map[*ast.Ident]types.Object{},
+				Uses:  map[*ast.Ident]types.Object{},
+			},
+			files: map[string]*ast.File{},
+		},
+		listed:   listed,
+		packages: map[string]*types.Package{},
+		std:      importer.ForCompiler(fset, "gc", nil),
+	}
+	pkg, err := l.load(root.ImportPath)
+	if err != nil {
+		return nil, err
+	}
+	l.Types = pkg
+	return &l.Package, nil
+}
+
+type loader struct {
+	Package
+	listed   map[string]*listedPackage
+	packages map[string]*types.Package // the packages loaded so far, by import path
+	std      types.Importer
+}
+
+func (l *loader) load(path string) (*types.Package, error) {
+	if pkg, ok := l.packages[path]; ok {
+		return pkg, nil
+	}
+	p, ok := l.listed[path]
+	if !ok {
+		return nil, fmt.Errorf("avroschema: package %s is not listed", path)
+	}
+	if p.Error != nil {
+		return nil, fmt.Errorf("avroschema: loading %s: %s", path, p.Error.Err)
+	}
+	if p.Standard {
+		return l.std.Import(path)
+	}
+
+	var files []*ast.File
+	for _, name := range append(p.GoFiles, p.CgoFiles...) {
+		filename := filepath.Join(p.Dir, name)
+		f, err := parser.ParseFile(l.fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("avroschema: loading %s: %w", path, err)
+		}
+		l.files[filename] = f
+		files = append(files, f)
+	}
+
+	conf := &types.Config{
+		Importer: importerFunc(func(imported string) (*types.Package, error) {
+			// e.g. vendored packages
+			if mapped, ok := p.ImportMap[imported]; ok {
+				imported = mapped
+			}
+			return l.load(imported)
+		}),
+		FakeImportC: true,
+	}
+	pkg, err := conf.Check(path, l.fset, files, l.info)
+	if err != nil {
+		return nil, fmt.Errorf("avroschema: loading %s: %w", path, err)
+	}
+	l.packages[path] = pkg
+	return pkg, nil
+}
+
+/*
+The declaration of the function or method fn, if loaded from source.
+*/
+func (p *Package) funcDecl(fn *types.Func) *ast.FuncDecl {
+	f := p.file(fn.Pos())
+	if f == nil {
+		return nil
+	}
+	for _, decl := range f.Decls {
+		if fd, ok := decl.(*ast.FuncDecl); ok && fd.Name.Pos() == fn.Pos() {
+			return fd
+		}
+	}
+	return nil
+}
+
+/*
+The initializer of the package level variable v, if loaded from source.
+*/
+func (p *Package) varInit(v *types.Var) ast.Expr {
+	f := p.file(v.Pos())
+	if f == nil {
+		return nil
+	}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if name.Pos() == v.Pos() && len(vs.Values) == len(vs.Names) {
+					return vs.Values[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Package) file(pos token.Pos) *ast.File {
+	tf := p.fset.File(pos)
+	if tf == nil {
+		return nil
+	}
+	return p.files[tf.Name()]
+}
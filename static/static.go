@@ -0,0 +1,384 @@
+/*
+Package static reflects Go types from the source of their package, type-checked with go/types, so that
+packages which cannot be imported into a generator, e.g. main packages, can be reflected as well.
+It is kept apart from package avroschema, so that programs reflecting at runtime don't link go/types.
+*/
+package static
+
+import (
+	"errors"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wirelessr/avroschema"
+)
+
+/*
+Reflect reflects the struct type named name in pkg into a record schema with r, as r.ReflectSchema
+does with a value of it.
+
+The same rules apply, except that the options working on a reflect.Type, i.e. Mapper, Namer,
+Namespacer, Enums and Unions, are ignored, and the schema is not cached. The symbols of enums
+are read from the source of their AvroEnumSymbols method, which must return a slice literal of
+constants or a package variable initialized with one, or else are the constants of the enum type
+in the order of their declaration. The same holds for AvroEnumDefault, returning a constant.
+*/
+func Reflect(r *avroschema.Reflector, pkg *Package, name string) (*avroschema.AvroSchema, error) {
+	obj, ok := pkg.Types.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, &avroschema.StaticTypeError{Type: pkg.Types.Path() + "." + name, Path: name, Reason: "no such type"}
+	}
+	t := unalias(obj.Type())
+	if named, ok := t.(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return nil, &avroschema.StaticTypeError{Type: typeKey(t), Path: name, Reason: "generic type"}
+	}
+	if kindOf(t) != reflect.Struct {
+		return nil, &avroschema.StaticTypeError{Type: typeKey(t), Path: name, Reason: "not a struct type"}
+	}
+
+	ts := &typeSystem{pkg: pkg, types: map[string]types.Type{}}
+	return avroschema.ReflectWith[string](r, ts, ts.key(t))
+}
+
+/*
+The TypeSystem of the types of a Package, identified by their typeKey.
+*/
+type typeSystem struct {
+	pkg   *Package
+	types map[string]types.Type // the types met so far, by typeKey
+}
+
+/*
+The key of t, remembering t to be looked up by it.
+*/
+func (s *typeSystem) key(t types.Type) string {
+	t = unalias(t)
+	key := typeKey(t)
+	s.types[key] = t
+	return key
+}
+
+func (s *typeSystem) Kind(key string) reflect.Kind { return kindOf(s.types[key]) }
+func (s *typeSystem) Elem(key string) string       { return s.key(elemOf(s.types[key])) }
+func (s *typeSystem) Name(key string) string       { return nameOf(s.types[key]) }
+func (s *typeSystem) String(key string) string     { return key }
+
+func (s *typeSystem) Key(key string) string {
+	return s.key(s.types[key].Underlying().(*types.Map).Key())
+}
+
+func (s *typeSystem) Len(key string) int {
+	return int(s.types[key].Underlying().(*types.Array).Len())
+}
+
+/*
+The package path of t as reflect.Type.PkgPath reports it, in which main packages have the path main.
+*/
+func (s *typeSystem) PkgPath(key string) string {
+	named, ok := s.types[key].(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	pkg := named.Obj().Pkg()
+	if pkg.Name() == "main" {
+		return "main"
+	}
+	return pkg.Path()
+}
+
+func (s *typeSystem) Fields(key string) []avroschema.Field[string] {
+	st := s.types[key].Underlying().(*types.Struct)
+	ret := make([]avroschema.Field[string], st.NumFields())
+	for i := range ret {
+		v := st.Field(i)
+		ret[i] = avroschema.Field[string]{Name: v.Name(), Embedded: v.Embedded(), Tag: reflect.StructTag(st.Tag(i)), Type: s.key(v.Type())}
+	}
+	return ret
+}
+
+func (s *typeSystem) MarshalsText(key string) bool {
+	return implements(s.types[key], textMarshalerType)
+}
+
+/*
+Types mapped specially by the Reflector, by typeKey.
+*/
+var wellKnownTypes = func() map[string]reflect.Type {
+	ret := map[string]reflect.Type{}
+	for _, t := range []reflect.Type{
+		reflect.TypeOf(time.Time{}), reflect.TypeOf(time.Duration(0)),
+		reflect.TypeOf(avroschema.Date{}), reflect.TypeOf(avroschema.TimeOfDay(0)),
+		reflect.TypeOf(big.Rat{}), reflect.TypeOf(big.Float{}),
+	} {
+		ret[t.PkgPath()+"."+t.Name()] = t
+	}
+	return ret
+}()
+
+func (s *typeSystem) Runtime(key string) (reflect.Type, bool) {
+	t, ok := wellKnownTypes[key]
+	return t, ok
+}
+
+/*
+The identity of t within a walk, identical types having the same key.
+*/
+func typeKey(t types.Type) string {
+	return types.TypeString(t, nil)
+}
+
+/*
+Aliases, i.e. *types.Alias as of Go 1.22, stand for the type they denote.
+*/
+func unalias(t types.Type) types.Type {
+	for {
+		alias, ok := t.(interface{ Rhs() types.Type })
+		if !ok {
+			return t
+		}
+		t = alias.Rhs()
+	}
+}
+
+var basicKinds = map[types.BasicKind]reflect.Kind{
+	types.Bool:          reflect.Bool,
+	types.Int:           reflect.Int,
+	types.Int8:          reflect.Int8,
+	types.Int16:         reflect.Int16,
+	types.Int32:         reflect.Int32,
+	types.Int64:         reflect.Int64,
+	types.Uint:          reflect.Uint,
+	types.Uint8:         reflect.Uint8,
+	types.Uint16:        reflect.Uint16,
+	types.Uint32:        reflect.Uint32,
+	types.Uint64:        reflect.Uint64,
+	types.Uintptr:       reflect.Uintptr,
+	types.Float32:       reflect.Float32,
+	types.Float64:       reflect.Float64,
+	types.Complex64:     reflect.Complex64,
+	types.Complex128:    reflect.Complex128,
+	types.String:        reflect.String,
+	types.UnsafePointer: reflect.UnsafePointer,
+}
+
+/*
+The reflect.Kind values of type t would have.
+*/
+func kindOf(t types.Type) reflect.Kind {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		return basicKinds[u.Kind()]
+	case *types.Pointer:
+		return reflect.Ptr
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Array:
+		return reflect.Array
+	case *types.Map:
+		return reflect.Map
+	case *types.Struct:
+		return reflect.Struct
+	case *types.Interface:
+		return reflect.Interface
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Signature:
+		return reflect.Func
+	}
+	return reflect.Invalid
+}
+
+/*
+The element type of pointer, slice, array and map types.
+*/
+func elemOf(t types.Type) types.Type {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		return unalias(u.Elem())
+	case *types.Slice:
+		return unalias(u.Elem())
+	case *types.Array:
+		return unalias(u.Elem())
+	case *types.Map:
+		return unalias(u.Elem())
+	}
+	return nil
+}
+
+/*
+The name reflect.Type.Name reports for t, with the type arguments of generic types.
+*/
+func nameOf(t types.Type) string {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return ""
+	}
+	args := named.TypeArgs()
+	if args.Len() == 0 {
+		return named.Obj().Name()
+	}
+	ret := make([]string, args.Len())
+	for i := range ret {
+		ret[i] = typeKey(args.At(i))
+	}
+	return named.Obj().Name() + "[" + strings.Join(ret, ",") + "]"
+}
+
+var (
+	avroEnumType          = methodInterface("AvroEnumSymbols", nil, types.NewSlice(types.Typ[types.String]))
+	avroEnumDefaulterType = methodInterface("AvroEnumDefault", nil, types.Typ[types.String])
+	textMarshalerType     = methodInterface("MarshalText", nil,
+		types.NewSlice(types.Typ[types.Byte]), types.Universe.Lookup("error").Type())
+)
+
+/*
+An interface of the single method name, the static counterpart of e.g. AvroEnum.
+*/
+func methodInterface(name string, params []types.Type, results ...types.Type) *types.Interface {
+	vars := func(ts []types.Type) *types.Tuple {
+		ret := make([]*types.Var, len(ts))
+		for i, t := range ts {
+			ret[i] = types.NewParam(token.NoPos, nil, "", t)
+		}
+		return types.NewTuple(ret...)
+	}
+	sig := types.NewSignatureType(nil, nil, nil, vars(params), vars(results), false)
+	return types.NewInterfaceType([]*types.Func{types.NewFunc(token.NoPos, nil, name, sig)}, nil).Complete()
+}
+
+/*
+Whether t or *t implements iface, so that methods with pointer receivers are found as well.
+*/
+func implements(t types.Type, iface *types.Interface) bool {
+	if kindOf(t) == reflect.Interface {
+		return false
+	}
+	return types.Implements(t, iface) || types.Implements(types.NewPointer(t), iface)
+}
+
+/*
+Read the enum declaration of t from the source of its AvroEnum methods, falling back to its constants.
+*/
+func (s *typeSystem) Enum(key string) (*avroschema.EnumSpec, error) {
+	t := s.types[key]
+	named, ok := t.(*types.Named)
+	if !ok || !implements(t, avroEnumType) {
+		return nil, nil
+	}
+
+	spec := &avroschema.EnumSpec{}
+	if symbols, ok := s.pkg.stringsOf(s.pkg.returned(method(named, "AvroEnumSymbols"))); ok {
+		spec.Symbols = symbols
+	} else if kindOf(t) == reflect.String {
+		spec.Symbols = s.pkg.constants(named)
+	}
+	if len(spec.Symbols) == 0 {
+		return nil, errors.New("cannot read the enum symbols from source")
+	}
+
+	if implements(t, avroEnumDefaulterType) {
+		def, ok := s.pkg.stringOf(s.pkg.returned(method(named, "AvroEnumDefault")))
+		if !ok {
+			return nil, errors.New("cannot read the enum default from source")
+		}
+		spec.Default = def
+	}
+	return spec, nil
+}
+
+func method(t *types.Named, name string) *types.Func {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, t.Obj().Pkg(), name)
+	fn, _ := obj.(*types.Func)
+	return fn
+}
+
+/*
+The expression the body of fn consists of returning, if loaded from source.
+*/
+func (p *Package) returned(fn *types.Func) ast.Expr {
+	if fn == nil {
+		return nil
+	}
+	decl := p.funcDecl(fn)
+	if decl == nil || decl.Body == nil || len(decl.Body.List) != 1 {
+		return nil
+	}
+	ret, ok := decl.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil
+	}
+	return ret.Results[0]
+}
+
+/*
+The strings of a slice literal of constants, or of the package variable initialized with one.
+*/
+func (p *Package) stringsOf(expr ast.Expr) ([]string, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return p.stringsOf(e.X)
+	case *ast.Ident, *ast.SelectorExpr:
+		ident, ok := e.(*ast.Ident)
+		if !ok {
+			ident = e.(*ast.SelectorExpr).Sel
+		}
+		if v, ok := p.info.Uses[ident].(*types.Var); ok && v.Parent() == v.Pkg().Scope() {
+			return p.stringsOf(p.varInit(v))
+		}
+	case *ast.CompositeLit:
+		ret := make([]string, len(e.Elts))
+		for i, elt := range e.Elts {
+			s, ok := p.stringOf(elt)
+			if !ok {
+				return nil, false
+			}
+			ret[i] = s
+		}
+		return ret, true
+	}
+	return nil, false
+}
+
+/*
+The value of a constant string expression.
+*/
+func (p *Package) stringOf(expr ast.Expr) (string, bool) {
+	if expr == nil {
+		return "", false
+	}
+	tv, ok := p.info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+/*
+The values of the string constants of type t, in the order of their declaration.
+*/
+func (p *Package) constants(t *types.Named) []string {
+	scope := t.Obj().Pkg().Scope()
+	var consts []*types.Const
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if ok && types.Identical(c.Type(), t) && c.Val().Kind() == constant.String {
+			consts = append(consts, c)
+		}
+	}
+	sort.Slice(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+
+	ret := make([]string, len(consts))
+	for i, c := range consts {
+		ret[i] = constant.StringVal(c.Val())
+	}
+	return ret
+}
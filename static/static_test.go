@@ -0,0 +1,60 @@
+package static
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/wirelessr/avroschema"
+)
+
+func TestReflectMain(t *testing.T) {
+	pkg, err := Load("testdata/event")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := Reflect(&avroschema.Reflector{Namespace: "com.example"}, pkg, "Event")
+	assert.Nil(t, err)
+	assert.Equal(t, &avroschema.AvroSchema{
+		Name:      "Event",
+		Type:      "record",
+		Namespace: "com.example",
+		Fields: []*avroschema.AvroSchema{
+			{Name: "name", Type: "string"},
+			{Name: "kind", Type: &avroschema.AvroSchema{Name: "Kind", Type: "enum", Namespace: "com.example", Symbols: []string{"CREATED", "DELETED"}}},
+			{Name: "payload", Type: &avroschema.AvroSchema{Name: "Payload", Type: "record", Namespace: "com.example", Fields: []*avroschema.AvroSchema{
+				{Name: "data", Type: "bytes"},
+			}}, Doc: "raw payload"},
+		},
+	}, schema)
+}
+
+func TestReflectError(t *testing.T) {
+	pkg, err := Load("testdata/event")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		reflector *avroschema.Reflector
+		typ       string
+		expected  string
+	}{
+		{"no such type", &avroschema.Reflector{}, "Missing", "avroschema: github.com/wirelessr/avroschema/static/testdata/event.Missing at Missing: no such type"},
+		{"not a struct", &avroschema.Reflector{}, "Names", "avroschema: github.com/wirelessr/avroschema/static/testdata/event.Names at Names: not a struct type"},
+		{"generic", &avroschema.Reflector{}, "Generic", "avroschema: github.com/wirelessr/avroschema/static/testdata/event.Generic[T any] at Generic: generic type"},
+		{"unsupported in strict mode", &avroschema.Reflector{Strict: true}, "Stream", "avroschema: chan github.com/wirelessr/avroschema/static/testdata/event.Event at Stream.events: unsupported type"},
+		{"enum symbols not constant", &avroschema.Reflector{}, "Computed", "avroschema: github.com/wirelessr/avroschema/static/testdata/event.Computable at Computed.kind: cannot read the enum symbols from source"},
+		{"invalid enum", &avroschema.Reflector{}, "Duplicated", `avroschema: github.com/wirelessr/avroschema/static/testdata/event.Twice at Duplicated.kind: invalid enum: duplicate symbol "A"`},
+		{"name collision", &avroschema.Reflector{NameMapping: map[string]string{"Payload": "Event"}}, "Colliding", "avroschema: github.com/wirelessr/avroschema/static/testdata/event.Payload at Colliding.event.payload: shares the name Event with github.com/wirelessr/avroschema/static/testdata/event.Event"},
+	}
+
+	for _, test := range tests {
+		_, err := Reflect(test.reflector, pkg, test.typ)
+		assert.EqualError(t, err, test.expected, test.name)
+	}
+
+	_, err = Load("testdata/missing")
+	assert.NotNil(t, err)
+}
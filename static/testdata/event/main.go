@@ -0,0 +1,70 @@
+// Command event holds Go types for the tests of package static, as a main package which cannot be imported.
+package main
+
+// Event is something that happened,
+// as told by its source.
+//
+// It is never changed.
+type Event struct {
+	// Name is unique per source.
+	Name    string  `json:"name"`
+	Kind    Kind    `json:"kind"` // what happened
+	Payload Payload `json:"payload" avro:"payload,doc=raw payload"`
+}
+
+type (
+	// Payload is opaque.
+	Payload struct {
+		Data []byte `json:"data"`
+	}
+
+	// Kind of an event.
+	//
+	//avroschema:generate
+	Kind int
+)
+
+func (Kind) AvroEnumSymbols() []string {
+	return []string{"CREATED", "DELETED"}
+}
+
+type Stream struct {
+	Events chan Event `json:"events"`
+}
+
+type Computed struct {
+	Kind Computable `json:"kind"`
+}
+
+type Computable int
+
+func (Computable) AvroEnumSymbols() []string {
+	return symbols()
+}
+
+func symbols() []string {
+	return []string{"A"}
+}
+
+type Duplicated struct {
+	Kind Twice `json:"kind"`
+}
+
+type Twice string
+
+func (Twice) AvroEnumSymbols() []string {
+	return []string{"A", "A"}
+}
+
+type Colliding struct {
+	Event   Event   `json:"event"`
+	Payload Payload `json:"payload"`
+}
+
+type Generic[T any] struct {
+	Value T `json:"value"`
+}
+
+type Names []string
+
+func main() {}
@@ -0,0 +1,161 @@
+package avroschema
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+)
+
+/*
+Date is a calendar date without time of day, emitted with the date logical type.
+*/
+type Date struct {
+	time.Time
+}
+
+/*
+TimeOfDay is the time elapsed since midnight, emitted with the time-millis
+or time-micros logical type depending on the Reflector's TimePrecision.
+*/
+type TimeOfDay time.Duration
+
+/*
+TimePrecision selects the logical types of time.Time and TimeOfDay fields.
+*/
+type TimePrecision int
+
+const (
+	Millis TimePrecision = iota
+	Micros
+	Nanos
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	durationType  = reflect.TypeOf(time.Duration(0))
+	dateType      = reflect.TypeOf(Date{})
+	timeOfDayType = reflect.TypeOf(TimeOfDay(0))
+)
+
+// underlying Avro type of every temporal logical type
+var temporalTypes = map[string]string{
+	"date":                   "int",
+	"time-millis":            "int",
+	"time-micros":            "long",
+	"timestamp-millis":       "long",
+	"timestamp-micros":       "long",
+	"timestamp-nanos":        "long",
+	"local-timestamp-millis": "long",
+	"local-timestamp-micros": "long",
+	"local-timestamp-nanos":  "long",
+	"duration":               "fixed",
+}
+
+var (
+	timestampLogicalTypes = []string{
+		"timestamp-millis", "timestamp-micros", "timestamp-nanos",
+		"local-timestamp-millis", "local-timestamp-micros", "local-timestamp-nanos", "date",
+	}
+	timeOfDayLogicalTypes = []string{"time-millis", "time-micros"}
+)
+
+/*
+Map time.Time, time.Duration, Date and TimeOfDay to their logical types.
+The `logicalType` tag option overrides the Reflector settings for a single field.
+*/
+func (w *walker[T]) handleTemporal(t reflect.Type, tag *avroTag, path string) (any, bool, error) {
+	var logicalType string
+	var allowed []string
+	switch t {
+	case timeType:
+		logicalType = w.timestampLogicalType()
+		allowed = timestampLogicalTypes
+	case dateType:
+		logicalType = "date"
+		allowed = []string{"date"}
+	case timeOfDayType:
+		logicalType = "time-millis"
+		if w.TimePrecision != Millis {
+			// there is no time-nanos
+			logicalType = "time-micros"
+		}
+		allowed = timeOfDayLogicalTypes
+	case durationType:
+		if w.DurationAsFixed {
+			logicalType = "duration"
+		}
+		allowed = []string{"duration"}
+	default:
+		return nil, false, nil
+	}
+
+	if tag.LogicalType != "" {
+		if !slices.Contains(allowed, tag.LogicalType) {
+			return nil, true, &InvalidTagError{Tag: tag.Raw, Path: path, Reason: fmt.Sprintf("logical type %s does not apply to %s", tag.LogicalType, t)}
+		}
+		logicalType = tag.LogicalType
+	}
+
+	switch logicalType {
+	case "":
+		// durations are plain longs unless DurationAsFixed is set
+		return "long", true, nil
+	case "duration":
+		ret, err := w.handleDuration(path)
+		return ret, true, err
+	}
+	return &AvroSchema{Type: temporalTypes[logicalType], LogicalType: logicalType}, true, nil
+}
+
+func (r *Reflector) timestampLogicalType() string {
+	ret := "timestamp-"
+	if r.LocalTimestamps {
+		ret = "local-timestamp-"
+	}
+	switch r.TimePrecision {
+	case Micros:
+		return ret + "micros"
+	case Nanos:
+		return ret + "nanos"
+	default:
+		return ret + "millis"
+	}
+}
+
+/*
+Integers may carry the temporal logical types of their Avro type, e.g. an int64 of timestamp-micros,
+so that the tag states the precision they hold. Kind is the kind of the type t.
+*/
+func logicalInteger(t string, kind reflect.Kind, tag *avroTag, path string) (any, error) {
+	var typ string
+	switch kind {
+	case reflect.Int64, reflect.Uint64:
+		typ = "long"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		typ = "int"
+	}
+	if typ == "" || temporalTypes[tag.LogicalType] != typ {
+		return nil, &InvalidTagError{Tag: tag.Raw, Path: path, Reason: fmt.Sprintf("logical type %s does not apply to %s", tag.LogicalType, t)}
+	}
+	return &AvroSchema{Type: typ, LogicalType: tag.LogicalType}, nil
+}
+
+/*
+The duration logical type annotates a fixed of 12 bytes holding months, days and milliseconds.
+*/
+func (w *walker[T]) handleDuration(path string) (any, error) {
+	namespace, ref, err := w.declareSynthetic(durationType, "duration", path)
+	if err != nil || ref != "" {
+		return ref, err
+	}
+
+	return &AvroSchema{
+		Name:        "duration",
+		Type:        "fixed",
+		Namespace:   namespace,
+		Size:        12,
+		LogicalType: "duration",
+	}, nil
+}
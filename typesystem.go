@@ -0,0 +1,150 @@
+package avroschema
+
+import (
+	"encoding"
+	"reflect"
+)
+
+/*
+TypeSystem describes the Go types a Reflector walks, so that types other than reflect.Type,
+e.g. those package static reads from source, are reflected by the same rules with ReflectWith.
+A T identifies a type, identical types must be equal. Aliases stand for the type they denote.
+*/
+type TypeSystem[T comparable] interface {
+	Kind(t T) reflect.Kind            // the kind of the values of t
+	Elem(t T) T                       // the element type of pointer, slice, array and map types
+	Key(t T) T                        // the key type of map types
+	Len(t T) int                      // the length of array types
+	Name(t T) string                  // as reflect.Type.Name reports it, with the type arguments of generic types
+	PkgPath(t T) string               // as reflect.Type.PkgPath reports it, main for main packages
+	String(t T) string                // for error messages
+	Fields(t T) []Field[T]            // the fields of struct types, as declared
+	MarshalsText(t T) bool            // whether t or *t implements encoding.TextMarshaler
+	Enum(t T) (*EnumSpec, error)      // the declaration of t if it implements AvroEnum, else nil
+	Runtime(t T) (reflect.Type, bool) // the reflect.Type of t, if known, at least for the types mapped specially, e.g. time.Time
+}
+
+/*
+Field is a field of a struct type as declared, for TypeSystem.Fields.
+*/
+type Field[T comparable] struct {
+	Name     string
+	Embedded bool
+	Tag      reflect.StructTag
+	Type     T
+}
+
+/*
+The TypeSystem of reflect.Type, which a Reflector walks by default.
+*/
+type runtimeTypes struct{}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+func (runtimeTypes) Kind(t reflect.Type) reflect.Kind            { return t.Kind() }
+func (runtimeTypes) Elem(t reflect.Type) reflect.Type            { return t.Elem() }
+func (runtimeTypes) Key(t reflect.Type) reflect.Type             { return t.Key() }
+func (runtimeTypes) Len(t reflect.Type) int                      { return t.Len() }
+func (runtimeTypes) Name(t reflect.Type) string                  { return t.Name() }
+func (runtimeTypes) PkgPath(t reflect.Type) string               { return t.PkgPath() }
+func (runtimeTypes) String(t reflect.Type) string                { return t.String() }
+func (runtimeTypes) Runtime(t reflect.Type) (reflect.Type, bool) { return t, true }
+
+func (runtimeTypes) Fields(t reflect.Type) []Field[reflect.Type] {
+	ret := make([]Field[reflect.Type], t.NumField())
+	for i := range ret {
+		sf := t.Field(i)
+		ret[i] = Field[reflect.Type]{Name: sf.Name, Embedded: sf.Anonymous, Tag: sf.Tag, Type: sf.Type}
+	}
+	return ret
+}
+
+func (runtimeTypes) MarshalsText(t reflect.Type) bool {
+	_, ok := implementor(t, textMarshalerType)
+	return ok
+}
+
+func (runtimeTypes) Enum(t reflect.Type) (*EnumSpec, error) {
+	// there is no value to call the methods of an interface on
+	if t.Kind() == reflect.Interface {
+		return nil, nil
+	}
+	v, ok := implementor(t, avroEnumType)
+	if !ok {
+		return nil, nil
+	}
+	spec := &EnumSpec{Symbols: v.Interface().(AvroEnum).AvroEnumSymbols()}
+	if v.Type().Implements(avroEnumDefaulterType) {
+		spec.Default = v.Interface().(AvroEnumDefaulter).AvroEnumDefault()
+	}
+	return spec, nil
+}
+
+/*
+Return a value of t or *t implementing iface, so that methods with pointer receivers are found as well.
+*/
+func implementor(t reflect.Type, iface reflect.Type) (reflect.Value, bool) {
+	if t.Implements(iface) {
+		return reflect.Zero(t), true
+	}
+	if reflect.PointerTo(t).Implements(iface) {
+		return reflect.New(t), true
+	}
+	return reflect.Value{}, false
+}
+
+/*
+The walk of a root type of a TypeSystem, with the per-call state of the Reflector it applies.
+The options working on a reflect.Type, i.e. Mapper, Namer, Namespacer, Enums and Unions,
+only apply to reflect.Type values.
+*/
+type walker[T comparable] struct {
+	*Reflector
+	types       TypeSystem[T]
+	defined     map[T]string   // full names of the named types defined so far
+	claimed     map[string]any // the type claiming each full name, a T or a reflect.Type for synthetic types
+	tagDefaults []tagDefault   // the tag defaults of the fields reflected so far
+	enclosing   string         // the namespace of the record being walked, which names within it are relative to
+}
+
+/*
+ReflectWith reflects the struct type t of the type system ts into a record schema, as ReflectSchema
+does with a value of it, e.g. for the types package static reads from source. The options working
+on a reflect.Type, i.e. Mapper, Namer, Namespacer, Enums and Unions, only apply to reflect.Type values,
+and the schema is not cached. Types other than structs are rejected.
+*/
+func ReflectWith[T comparable](r *Reflector, ts TypeSystem[T], t T) (*AvroSchema, error) {
+	w := &walker[T]{Reflector: r, types: ts, defined: map[T]string{}, claimed: map[string]any{}}
+	if ts.Kind(t) != reflect.Struct {
+		if rt, ok := w.native(t); ok {
+			return nil, &InvalidRootError{Type: rt}
+		}
+		return nil, w.typeError(t, ts.Name(t), "not a struct type")
+	}
+
+	data, err := w.handleRecord(t, w.typeName(t, ""))
+	if err != nil {
+		return nil, err
+	}
+	// the walk starts afresh, so the root record cannot be a reference
+	root := data.(*AvroSchema)
+	if err := w.checkDefaults(root); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+/*
+The reflect.Type of t when walking reflect.Type values, to which the options working on them apply.
+*/
+func (w *walker[T]) native(t T) (reflect.Type, bool) {
+	rt, ok := any(t).(reflect.Type)
+	return rt, ok
+}
+
+/*
+The error about t when t is not a reflect.Type, to report in place of e.g. an UnsupportedTypeError.
+*/
+func (w *walker[T]) typeError(t T, path, reason string) error {
+	return &StaticTypeError{Type: w.types.String(t), Path: path, Reason: reason}
+}
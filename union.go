@@ -0,0 +1,81 @@
+package avroschema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+/*
+UnionSpec declares the implementations of an interface registered in
+Reflector.Unions. Fields of the interface type are emitted as a union of
+the members, led by null if Nullable is set.
+*/
+type UnionSpec struct {
+	Members  []reflect.Type
+	Nullable bool
+}
+
+/*
+Return type is the union, i.e. a slice of the member schemas.
+Unions only apply to reflect.Type values, so T is a reflect.Type.
+*/
+func (w *walker[T]) handleUnion(t reflect.Type, spec UnionSpec, path string) (any, error) {
+	if len(spec.Members) == 0 {
+		return nil, &InvalidUnionError{Type: t, Path: path, Reason: "no members"}
+	}
+
+	var ret []any
+	if spec.Nullable {
+		ret = append(ret, "null")
+	}
+	seen := make(map[string]bool, len(spec.Members))
+	for _, member := range spec.Members {
+		if !member.Implements(t) && !reflect.PointerTo(member).Implements(t) {
+			return nil, &InvalidUnionError{Type: t, Path: path, Reason: fmt.Sprintf("%s does not implement it", member)}
+		}
+
+		branch, err := w.reflectType(any(member).(T), path, &avroTag{})
+		if err != nil {
+			return nil, err
+		}
+		// a union may not hold two schemas of the same type, except for named types with different names
+		key := branchKey(branch)
+		if seen[key] {
+			return nil, &InvalidUnionError{Type: t, Path: path, Reason: fmt.Sprintf("%s duplicates %s", member, key)}
+		}
+		seen[key] = true
+		ret = append(ret, branch)
+	}
+	return ret, nil
+}
+
+func branchKey(branch any) string {
+	s, ok := branch.(*AvroSchema)
+	if !ok {
+		return fmt.Sprint(branch)
+	}
+	switch s.Type {
+	case "record", "enum", "fixed":
+		return qualify(s.Namespace, s.Name)
+	}
+	return fmt.Sprint(s.Type)
+}
+
+/*
+Make a field schema nullable, without nesting unions which Avro forbids.
+*/
+func nullable(ret any, nullFirst bool) []any {
+	union, ok := ret.([]any)
+	if !ok {
+		union = []any{ret}
+	}
+	for _, branch := range union {
+		if branch == "null" {
+			return union
+		}
+	}
+	if nullFirst {
+		return append([]any{"null"}, union...)
+	}
+	return append(union, "null")
+}
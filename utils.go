@@ -1,27 +1,201 @@
 package avroschema
 
-import "strings"
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var nameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+/*
+Avro names, enum symbols included, must match [A-Za-z_][A-Za-z0-9_]*.
+*/
+func isValidName(name string) bool {
+	return nameRegexp.MatchString(name)
+}
 
 type structTag struct {
 	Name     string
 	Optional bool
 	Inline   bool
+	Skip     bool // the tag is exactly "-"
+	Quoted   bool // the ,string option of encoding/json
 }
 
 func parseStructTag(tag string) *structTag {
 	tags := strings.Split(tag, ",")
-	name := tags[0]
-	optional := false
-	inline := false
+	ret := &structTag{Name: tags[0], Skip: tag == "-"}
 
-	for _, tag := range tags {
+	for _, tag := range tags[1:] {
 		switch tag {
 		case "omitempty":
-			optional = true
+			ret.Optional = true
 
 		case "inline":
-			inline = true
+			ret.Inline = true
+
+		case "string":
+			ret.Quoted = true
+		}
+	}
+	return ret
+}
+
+/*
+avroTag is the parsed form of an `avro:"name,optional,default=0,doc=...,alias=old,order=descending"` tag.
+Commas inside option values can be escaped as `\,`.
+*/
+type avroTag struct {
+	Raw         string
+	Name        string
+	Skip        bool
+	Optional    bool
+	RawDefault  string
+	Default     any
+	HasDefault  bool
+	Doc         string
+	Aliases     []string
+	Order       string
+	Decimal     bool
+	BigDecimal  bool
+	Fixed       bool
+	Precision   int
+	Scale       int
+	HasScale    bool // the scale is set, even to 0
+	LogicalType string
+	UUID        bool
+	Quoted      bool
+}
+
+func parseAvroTag(tag string) (*avroTag, error) {
+	tokens := splitTag(tag)
+	ret := &avroTag{Raw: tag, Name: tokens[0]}
+	if ret.Name == "-" && len(tokens) == 1 {
+		ret.Skip = true
+		return ret, nil
+	}
+
+	for _, token := range tokens[1:] {
+		key, value, _ := strings.Cut(token, "=")
+		switch key {
+		case "optional":
+			ret.Optional = true
+		case "default":
+			ret.RawDefault = value
+			ret.HasDefault = true
+		case "doc":
+			ret.Doc = value
+		case "decimal":
+			ret.Decimal = true
+		case "big-decimal":
+			ret.Decimal = true
+			ret.BigDecimal = true
+		case "fixed":
+			ret.Fixed = true
+		case "uuid":
+			ret.UUID = true
+		case "logicalType":
+			ret.LogicalType = value
+		default:
+			if err := ret.parseOption(key, value, token); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ret, nil
+}
+
+/*
+Parse the options of an avro tag whose values are checked, i.e. alias, order, precision and scale.
+*/
+func (t *avroTag) parseOption(key, value, token string) error {
+	switch key {
+	case "alias":
+		// as ParseSchema accepts them
+		if value == "" || !isValidNamespace(value) {
+			return fmt.Errorf("invalid alias %q", value)
+		}
+		t.Aliases = append(t.Aliases, value)
+	case "order":
+		switch value {
+		case "ascending", "descending", "ignore":
+			t.Order = value
+		default:
+			return fmt.Errorf("invalid order %q", value)
+		}
+	case "precision", "scale":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid %s %q", key, value)
 		}
+		if key == "precision" {
+			t.Precision = n
+		} else {
+			t.Scale = n
+			t.HasScale = true
+		}
+	default:
+		return fmt.Errorf("unknown option %q", token)
+	}
+	return nil
+}
+
+func splitTag(tag string) []string {
+	var tokens []string
+	var token strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			token.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			tokens = append(tokens, token.String())
+			token.Reset()
+		default:
+			token.WriteByte(tag[i])
+		}
+	}
+	return append(tokens, token.String())
+}
+
+/*
+Decode a tag default into a value of the field's type.
+String fields take the raw text unless it is quoted, everything else must be valid JSON.
+A null default is explicit, i.e. Null. Kind is the kind of the field's type without any pointers.
+*/
+func decodeDefault(raw string, kind reflect.Kind) (any, error) {
+	if raw == "null" {
+		return Null{}, nil
+	}
+	if kind == reflect.String && !strings.HasPrefix(raw, `"`) {
+		return raw, nil
+	}
+
+	var ret any
+	if err := json.Unmarshal([]byte(raw), &ret); err != nil {
+		return nil, fmt.Errorf("invalid default %q: %w", raw, err)
+	}
+	return ret, nil
+}
+
+func (w *walker[T]) isContainer(t T) bool {
+	switch w.types.Kind(t) {
+	case reflect.Map:
+		return true
+	case reflect.Slice, reflect.Array:
+		return w.types.Kind(w.types.Elem(t)) != reflect.Uint8
+	}
+	return false
+}
+
+func isNilable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		return true
 	}
-	return &structTag{name, optional, inline}
+	return false
 }
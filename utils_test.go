@@ -1,6 +1,7 @@
 package avroschema
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -36,3 +37,81 @@ func TestGetNameAndOmit(t *testing.T) {
 		})
 	}
 }
+
+func TestStructTagSkipAndQuoted(t *testing.T) {
+	assert.True(t, parseStructTag("-").Skip)
+	assert.False(t, parseStructTag("-,").Skip)
+	assert.Equal(t, "-", parseStructTag("-,").Name)
+	assert.True(t, parseStructTag("count,string").Quoted)
+	assert.False(t, parseStructTag("string").Quoted)
+}
+
+func TestParseAvroTag(t *testing.T) {
+	tag, err := parseAvroTag(`price,optional,default=0,doc=Unit price\, in cents,alias=cost,alias=amount,order=descending`)
+	assert.Nil(t, err)
+	assert.Equal(t, "price", tag.Name)
+	assert.True(t, tag.Optional)
+	assert.True(t, tag.HasDefault)
+	assert.Equal(t, "0", tag.RawDefault)
+	assert.Equal(t, "Unit price, in cents", tag.Doc)
+	assert.Equal(t, []string{"cost", "amount"}, tag.Aliases)
+	assert.Equal(t, "descending", tag.Order)
+
+	tag, err = parseAvroTag("-")
+	assert.Nil(t, err)
+	assert.True(t, tag.Skip)
+
+	_, err = parseAvroTag("price,order=sideways")
+	assert.NotNil(t, err)
+
+	_, err = parseAvroTag("price,optinal")
+	assert.NotNil(t, err)
+
+	_, err = parseAvroTag("price,alias=old-price")
+	assert.NotNil(t, err)
+
+	_, err = parseAvroTag("price,alias=")
+	assert.NotNil(t, err)
+}
+
+func TestDecimalSize(t *testing.T) {
+	var tdata = []struct {
+		precision int
+		size      int
+	}{
+		{1, 1}, {2, 1}, {3, 2}, {9, 4}, {18, 8}, {19, 9}, {38, 16},
+	}
+
+	for _, tt := range tdata {
+		assert.Equal(t, tt.size, decimalSize(tt.precision), "precision %d", tt.precision)
+	}
+}
+
+type pkgPathStub struct {
+	reflect.Type
+	pkgPath string
+}
+
+func (s pkgPathStub) PkgPath() string { return s.pkgPath }
+
+func TestPackageNamespace(t *testing.T) {
+	var tdata = []struct {
+		pkgPath   string
+		namespace string
+	}{
+		{"github.com/acme/billing", "com.acme.billing"},
+		{"gitlab.com/acme/go-billing", "com.acme.go_billing"},
+		{"go.acme.com/shipping/v2", "com.acme.go.shipping.v2"},
+		{"example.com/1st", "com.example._1st"},
+		{"math/big", "math.big"},
+		{"main", "main"},
+		{"", ""},
+	}
+
+	for _, tt := range tdata {
+		t.Run(tt.pkgPath, func(t *testing.T) {
+			stub := pkgPathStub{reflect.TypeOf(0), tt.pkgPath}
+			assert.Equal(t, tt.namespace, PackageNamespace(stub))
+		})
+	}
+}
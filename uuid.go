@@ -0,0 +1,54 @@
+package avroschema
+
+import "reflect"
+
+/*
+Detect UUIDs, either tagged with the `uuid` option or a named type UUID over
+[16]byte which marshals to text, like github.com/google/uuid.UUID.
+*/
+func (w *walker[T]) isUUID(t T, tag *avroTag) bool {
+	if tag.UUID {
+		return true
+	}
+	return w.types.Name(t) == "UUID" && w.isByteArray(t, 16) && w.types.MarshalsText(t)
+}
+
+func (w *walker[T]) isByteArray(t T, size int) bool {
+	return w.types.Kind(t) == reflect.Array && w.types.Kind(w.types.Elem(t)) == reflect.Uint8 && w.types.Len(t) == size
+}
+
+/*
+UUIDs are strings annotated with the uuid logical type. Byte arrays may be
+emitted as fixed(16) instead, with UUIDAsFixed set.
+*/
+func (w *walker[T]) handleUUID(t T, tag *avroTag, path string) (any, error) {
+	isString := w.types.Kind(t) == reflect.String
+	if !isString && !w.isByteArray(t, 16) {
+		return nil, &InvalidTagError{Tag: tag.Raw, Path: path, Reason: "uuid applies to strings and [16]byte only"}
+	}
+
+	if isString || !w.UUIDAsFixed {
+		return &AvroSchema{Type: "string", LogicalType: "uuid"}, nil
+	}
+
+	var name, namespace, ref string
+	var err error
+	if w.types.Name(t) == "" {
+		name = "uuid"
+		namespace, ref, err = w.declareSynthetic(t, name, path)
+	} else {
+		name, namespace, ref, err = w.declareType(t, path)
+	}
+	if err != nil || ref != "" {
+		return ref, err
+	}
+
+	return &AvroSchema{
+		Name:        name,
+		Type:        "fixed",
+		Namespace:   namespace,
+		Doc:         w.typeDoc(t),
+		Size:        16,
+		LogicalType: "uuid",
+	}, nil
+}